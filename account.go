@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/myndshft/nemgo/crypto"
 	"github.com/pkg/errors"
 )
 
@@ -60,6 +61,17 @@ type AccountInfo struct {
 	// denotes the number of blocks that the account harvested so far.
 	// For a new account the number is 0.
 	HarvestedBlocks int
+	// MultisigInfo is only set for multisig accounts
+	MultisigInfo MultisigInfo
+}
+
+// MultisigInfo describes the cosignatories of a multisig account
+type MultisigInfo struct {
+	// CosignatoriesCount is the number of cosignatories of the account
+	CosignatoriesCount int
+	// MinCosignatories is the number of cosignatories needed to sign a
+	// transaction, 0 meaning all of them
+	MinCosignatories int
 }
 
 // AccountMetadata describes additional information for the account.
@@ -122,16 +134,16 @@ func (c Client) GetBatchAccountData(addresses []string) ([]AccountMetadataPair,
 }
 
 type accountName interface {
-	isValid() bool
+	validate(network byte) error
 	String() string
 }
 
 // Address is a Nem account address as a string
 type Address string
 
-func (a Address) isValid() bool {
-	// check if address is valid
-	return true
+// validate checks the length, alphabet, network and checksum of the address
+func (a Address) validate(network byte) error {
+	return crypto.ValidateAddress(string(a), network)
 }
 
 func (a Address) String() string {
@@ -141,9 +153,9 @@ func (a Address) String() string {
 // PublicKey is a Nem account public key as a string
 type PublicKey string
 
-func (pk PublicKey) isValid() bool {
-	// check if address is valid
-	return true
+// validate checks that the public key is 32 hex encoded bytes
+func (pk PublicKey) validate(network byte) error {
+	return crypto.ValidatePublicKey(string(pk))
 }
 
 func (pk PublicKey) String() string {
@@ -155,23 +167,27 @@ func (c Client) AccountData(acc accountName) (AccountMetadataPair, error) {
 	var data AccountMetadataPair
 	var req *http.Request
 	var err error
-	if acc.isValid() {
-		switch acc.(type) {
-		case Address:
-			c.url.Path = "/account/get"
-			req, err = c.buildReq(map[string]string{"address": acc.String()}, nil, http.MethodGet)
-			if err != nil {
-				return data, err
-			}
-		case PublicKey:
-			c.url.Path = "/account/get/from-public-key"
-			req, err = c.buildReq(map[string]string{"publicKey": acc.String()}, nil, http.MethodGet)
-			if err != nil {
-				return data, err
-			}
-		default:
-			return data, errors.New("Use an Address or PublicKey type")
+	if acc == nil {
+		return data, errors.New("Use an Address or PublicKey type")
+	}
+	if err = acc.validate(c.network); err != nil {
+		return data, errors.Wrapf(err, "invalid account %q", acc.String())
+	}
+	switch acc.(type) {
+	case Address:
+		c.url.Path = "/account/get"
+		req, err = c.buildReq(map[string]string{"address": acc.String()}, nil, http.MethodGet)
+		if err != nil {
+			return data, err
+		}
+	case PublicKey:
+		c.url.Path = "/account/get/from-public-key"
+		req, err = c.buildReq(map[string]string{"publicKey": acc.String()}, nil, http.MethodGet)
+		if err != nil {
+			return data, err
 		}
+	default:
+		return data, errors.New("Use an Address or PublicKey type")
 	}
 	body, err := c.request(req)
 	if err != nil {
@@ -225,7 +241,7 @@ func (c Client) AccountStatus(address string) (AccountMetadata, error) {
 
 // HarvestInfo is information about harvested blocks
 type HarvestInfo struct {
-	TimeStamp  int
+	TimeStamp  NetworkTime
 	Difficulty int
 	TotalFee   int
 	ID         int
@@ -252,10 +268,7 @@ func (c Client) Harvested(address string, hash string) ([]HarvestInfo, error) {
 
 // OwnedMosaic is an array of basic information about a mosaic
 type OwnedMosaic struct {
-	MosaicID struct {
-		NamespaceID string
-		Name        string
-	}
+	MosaicID MosaicID
 	Quantity int
 }
 
@@ -21,6 +21,7 @@ import (
 )
 
 var clientMock = Client{
+	network: Testnet,
 	url:     url.URL{},
 	request: sendReqMock}
 
@@ -111,6 +112,19 @@ func TestAccountInfoPrivateKey(t *testing.T) {
 	}
 }
 
+func TestAccountDataInvalid(t *testing.T) {
+	for _, acc := range []accountName{
+		Address("TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5"),
+		Address("TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5T"),
+		Address("NBMBTUB6JIXGBSETDJBMCGLB2GPTI6GMPAYFNH3P"),
+		PublicKey("a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1"),
+		nil} {
+		if _, err := clientMock.AccountData(acc); err == nil {
+			t.Fatalf("expected an error for %v", acc)
+		}
+	}
+}
+
 func TestGetDelegated(t *testing.T) {
 	want := AccountMetadataPair{
 		Account: AccountInfo{
@@ -176,17 +190,11 @@ func TestHarvested(t *testing.T) {
 func TestMosaicsOwned(t *testing.T) {
 	want := []OwnedMosaic{
 		OwnedMosaic{
-			MosaicID: struct {
-				NamespaceID string
-				Name        string
-			}{NamespaceID: "alice.drinks",
+			MosaicID: MosaicID{NamespaceID: "alice.drinks",
 				Name: "orange juice"},
 			Quantity: 123},
 		OwnedMosaic{
-			MosaicID: struct {
-				NamespaceID string
-				Name        string
-			}{NamespaceID: "alice.drinks",
+			MosaicID: MosaicID{NamespaceID: "alice.drinks",
 				Name: "orange juice"},
 			Quantity: 123}}
 	got, err := clientMock.MosaicsOwned("TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S")
@@ -0,0 +1,102 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+// RequestAnnounce is a serialized transaction and its signature, ready to
+// be announced to NIS
+type RequestAnnounce struct {
+	// Data is the hex encoded serialized transaction
+	Data string `json:"data"`
+	// Signature is the hex encoded signature of Data
+	Signature string `json:"signature"`
+}
+
+// NemAnnounceResult is returned by NIS when a transaction is announced
+type NemAnnounceResult struct {
+	// Type is 1 for a validation result, 2 for a heartbeat result and 4
+	// for a status result
+	Type int
+	// Code is the NIS validation result, 1 meaning success
+	Code int
+	// Message is the name of the validation result, such as "SUCCESS"
+	// or "FAILURE_INSUFFICIENT_BALANCE", Result decodes it
+	Message string
+	// TransactionHash is the hash of the announced transaction
+	TransactionHash hash
+	// InnerTransactionHash is the hash of the inner transaction of a
+	// multisig transaction
+	InnerTransactionHash hash
+}
+
+// SignTransaction serializes and signs a transaction with the key pair of
+// its signer
+func SignTransaction(kp crypto.KeyPair, tx TransactionEntity) (RequestAnnounce, error) {
+	var req RequestAnnounce
+	if !strings.EqualFold(tx.Common().Signer, kp.PublicKeyString()) {
+		return req, errors.New("key pair is not the signer of the transaction")
+	}
+	data, err := Serialize(tx)
+	if err != nil {
+		return req, err
+	}
+	sig, err := kp.Sign(data)
+	if err != nil {
+		return req, err
+	}
+	req.Data = hex.EncodeToString(data)
+	req.Signature = hex.EncodeToString(sig)
+	return req, nil
+}
+
+// Hash returns the hash NIS will assign to the announced transaction
+func (r RequestAnnounce) Hash() (string, error) {
+	data, err := hex.DecodeString(r.Data)
+	if err != nil {
+		return "", errors.Wrap(err, "data is not valid hex")
+	}
+	return TransactionHash(data), nil
+}
+
+// Announce sends a signed transaction to NIS
+func (c Client) Announce(ra RequestAnnounce) (NemAnnounceResult, error) {
+	var data NemAnnounceResult
+	payload, err := json.Marshal(ra)
+	if err != nil {
+		return data, err
+	}
+	c.url.Path = "/transaction/announce"
+	req, err := c.buildReq(nil, payload, http.MethodPost)
+	if err != nil {
+		return data, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
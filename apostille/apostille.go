@@ -0,0 +1,274 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apostille notarizes files on the NEM blockchain the same way
+// NanoWallet does. The hash of a file is put in the message of a transfer,
+// behind a header naming the hash algorithm, so anyone holding the file
+// can later check it existed when the transfer was confirmed.
+//
+// A public apostille holds the hash itself and is sent to the apostille
+// sink. A private apostille holds the hash signed by its owner and is
+// sent to an account dedicated to the file, see DedicatedKeyPair.
+package apostille
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/myndshft/nemgo"
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+// Header starts the message of every apostille, it is "fe" followed by
+// "NTY" in hex so NEM wallets show it as raw bytes
+const Header = "fe4e5459"
+
+// privateFlag is set on the algorithm byte of private apostilles
+const privateFlag = 0x80
+
+// Algorithm is a hash algorithm supported by apostilles, its value is
+// the byte following Header in a public apostille
+type Algorithm byte
+
+// The hash algorithms of NanoWallet. SHA3 is the Keccak variant NEM uses
+// everywhere, not the final FIPS 202 standard.
+const (
+	MD5      Algorithm = 0x01
+	SHA1     Algorithm = 0x02
+	SHA256   Algorithm = 0x03
+	SHA3_256 Algorithm = 0x08
+	SHA3_512 Algorithm = 0x09
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case MD5:
+		return "MD5"
+	case SHA1:
+		return "SHA1"
+	case SHA256:
+		return "SHA256"
+	case SHA3_256:
+		return "SHA3-256"
+	case SHA3_512:
+		return "SHA3-512"
+	default:
+		return "unknown"
+	}
+}
+
+func (a Algorithm) new() (hash.Hash, error) {
+	switch a {
+	case MD5:
+		return md5.New(), nil
+	case SHA1:
+		return sha1.New(), nil
+	case SHA256:
+		return sha256.New(), nil
+	case SHA3_256:
+		return sha3.NewLegacyKeccak256(), nil
+	case SHA3_512:
+		return sha3.NewLegacyKeccak512(), nil
+	default:
+		return nil, errors.Errorf("unknown hash algorithm 0x%02x", byte(a))
+	}
+}
+
+// Hash hashes the content of r with alg
+func Hash(r io.Reader, alg Algorithm) ([]byte, error) {
+	h, err := alg.new()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, errors.Wrap(err, "unable to read file")
+	}
+	return h.Sum(nil), nil
+}
+
+// Sink returns the address public apostilles are sent to on network
+func Sink(network byte) (string, error) {
+	switch network {
+	case nemgo.Mainnet:
+		return "NCZSJHLTIMESERVBVKOW6US64YDZG2PFGQCSV23J", nil
+	case nemgo.Testnet:
+		return "TC7MCY5AGJQXZQ4BN3BOPNXUVIGDJCOHBPGUM2GE", nil
+	default:
+		return "", errors.Errorf("unknown network 0x%x", network)
+	}
+}
+
+// DedicatedKeyPair derives the key pair of the account dedicated to the
+// private apostilles of the file called name. Its private key is the
+// SHA256 of the owner's signature of the SHA256 of name, so the owner can
+// always recover it and later transfer the account, and with it the
+// apostille, to someone else.
+func DedicatedKeyPair(owner crypto.KeyPair, name string) (crypto.KeyPair, error) {
+	h := sha256.Sum256([]byte(name))
+	sig, err := owner.Sign(h[:])
+	if err != nil {
+		return crypto.KeyPair{}, err
+	}
+	priv := sha256.Sum256(sig)
+	return crypto.FromPrivateKey(priv[:])
+}
+
+// Apostille is the content of an apostille transfer
+type Apostille struct {
+	Algorithm Algorithm
+	// Private is set when Data is the signed hash of the file
+	Private bool
+	// Hash is the hash of the file
+	Hash []byte
+	// Data follows Header and the algorithm byte in the message
+	Data []byte
+	// Recipient is the address the apostille is sent to
+	Recipient string
+}
+
+// New creates a public apostille of the content of r
+func New(r io.Reader, alg Algorithm, network byte) (Apostille, error) {
+	sink, err := Sink(network)
+	if err != nil {
+		return Apostille{}, err
+	}
+	h, err := Hash(r, alg)
+	if err != nil {
+		return Apostille{}, err
+	}
+	return Apostille{Algorithm: alg, Hash: h, Data: h, Recipient: sink}, nil
+}
+
+// NewPrivate creates a private apostille of the content of r signed by
+// owner and sent to the dedicated account of the file, see
+// DedicatedKeyPair
+func NewPrivate(r io.Reader, alg Algorithm, owner crypto.KeyPair, dedicated string, network byte) (Apostille, error) {
+	if err := crypto.ValidateAddress(dedicated, network); err != nil {
+		return Apostille{}, errors.Wrap(err, "invalid dedicated account")
+	}
+	h, err := Hash(r, alg)
+	if err != nil {
+		return Apostille{}, err
+	}
+	sig, err := owner.Sign(h)
+	if err != nil {
+		return Apostille{}, err
+	}
+	return Apostille{Algorithm: alg, Private: true, Hash: h, Data: sig, Recipient: dedicated}, nil
+}
+
+// Message returns the transfer message holding the apostille
+func (a Apostille) Message() nemgo.Message {
+	flag := byte(a.Algorithm)
+	if a.Private {
+		flag |= privateFlag
+	}
+	return nemgo.Message{
+		Payload: Header + hex.EncodeToString(append([]byte{flag}, a.Data...)),
+		Type:    nemgo.MessageTypePlain}
+}
+
+// Transfer builds the unsigned transfer of the apostille, sending no
+// XEM from signer to the recipient of the apostille
+func (a Apostille) Transfer(c nemgo.Client, signer nemgo.PublicKey, opts ...nemgo.TxOption) (nemgo.TransferTransaction, error) {
+	opts = append([]nemgo.TxOption{nemgo.WithMessage(a.Message())}, opts...)
+	return c.NewTransfer(signer, a.Recipient, 0, opts...)
+}
+
+// Parse reads an apostille from the message of a transfer
+func Parse(m nemgo.Message) (Apostille, error) {
+	if m.IsEncrypted() || !strings.HasPrefix(strings.ToLower(m.Payload), Header) {
+		return Apostille{}, errors.New("message is not an apostille")
+	}
+	b, err := hex.DecodeString(m.Payload[len(Header):])
+	if err != nil || len(b) == 0 {
+		return Apostille{}, errors.New("message is not an apostille")
+	}
+	a := Apostille{Algorithm: Algorithm(b[0] &^ privateFlag), Private: b[0]&privateFlag != 0, Data: b[1:]}
+	if _, err := a.Algorithm.new(); err != nil {
+		return Apostille{}, err
+	}
+	if !a.Private {
+		a.Hash = a.Data
+	}
+	return a, nil
+}
+
+// Certificate describes a verified apostille
+type Certificate struct {
+	Apostille
+	// Owner is the public key of the account that created the apostille
+	Owner string
+	// TimeStamp is when the apostille was created
+	TimeStamp nemgo.NetworkTime
+	// Height is the block the apostille was confirmed in
+	Height int
+	// TransactionHash is the hash of the apostille transfer
+	TransactionHash string
+}
+
+// Verify checks the file in r against the apostille in tx, which was
+// signed by the owner. Apostilles created from a multisig account are
+// verified against the inner transfer.
+func Verify(r io.Reader, tx nemgo.Transaction) (Certificate, error) {
+	if tx.OtherTrans != nil {
+		tx = *tx.OtherTrans
+	}
+	a, err := Parse(tx.Message)
+	if err != nil {
+		return Certificate{}, err
+	}
+	h, err := Hash(r, a.Algorithm)
+	if err != nil {
+		return Certificate{}, err
+	}
+	if a.Private {
+		owner, err := hex.DecodeString(tx.Signer)
+		if err != nil {
+			return Certificate{}, errors.Wrap(err, "signer is not valid hex")
+		}
+		if !crypto.Verify(owner, h, a.Data) {
+			return Certificate{}, errors.New("file does not match the apostille")
+		}
+		a.Hash = h
+	} else if !bytes.Equal(h, a.Hash) {
+		return Certificate{}, errors.New("file does not match the apostille")
+	}
+	a.Recipient = tx.Recipient
+	return Certificate{Apostille: a, Owner: tx.Signer, TimeStamp: tx.TimeStamp}, nil
+}
+
+// VerifyTransaction fetches the transaction with the given hash from NIS
+// and checks the file in r against its apostille
+func VerifyTransaction(c nemgo.Client, r io.Reader, txHash string) (Certificate, error) {
+	pair, err := c.TransactionByHash(txHash)
+	if err != nil {
+		return Certificate{}, err
+	}
+	cert, err := Verify(r, pair.Transaction)
+	if err != nil {
+		return Certificate{}, err
+	}
+	cert.Height = pair.Meta.Height
+	cert.TransactionHash = pair.Meta.Hash.Data
+	return cert, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apostille
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/myndshft/nemgo"
+	"github.com/myndshft/nemgo/crypto"
+)
+
+const (
+	file      = "Hello, apostille!"
+	ownerKey  = "575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced"
+	apostHash = "9b4bf2b6e0f5ff2a5c4c1d5b2e1c4d7f1a1e8e2f6f2c1b7f4f1d2a6c9e0b3d4a"
+)
+
+func owner(t *testing.T) crypto.KeyPair {
+	kp, err := crypto.FromHex(ownerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kp
+}
+
+func TestHash(t *testing.T) {
+	for _, tc := range []struct {
+		alg  Algorithm
+		want string
+	}{
+		{MD5, "900150983cd24fb0d6963f7d28e17f72"},
+		{SHA1, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{SHA256, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{SHA3_256, "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{SHA3_512, "18587dc2ea106b9a1563e32b3312421ca164c7f1f07bc922a9c83d77cea3a1e5d0c69910739025372dc14ac9642629379540c17e2a65b19d77aa511a9d00bb96"},
+	} {
+		got, err := Hash(strings.NewReader("abc"), tc.alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.want {
+			t.Fatalf("%v\nWanted: %v\n   Got: %x", tc.alg, tc.want, got)
+		}
+	}
+	if _, err := Hash(strings.NewReader("abc"), Algorithm(0x04)); err == nil {
+		t.Fatal("expected an error for an unknown algorithm")
+	}
+}
+
+func TestSink(t *testing.T) {
+	for _, network := range []byte{nemgo.Mainnet, nemgo.Testnet} {
+		sink, err := Sink(network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := crypto.ValidateAddress(sink, network); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Sink(0x60); err == nil {
+		t.Fatal("expected an error for an unknown network")
+	}
+}
+
+func TestPublicApostille(t *testing.T) {
+	a, err := New(strings.NewReader(file), SHA256, nemgo.Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := a.Message()
+	if want := Header + "03" + hex.EncodeToString(a.Hash); m.Payload != want {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, m.Payload)
+	}
+	if !m.IsHex() {
+		t.Fatal("apostille message should be a hex message")
+	}
+	tx := nemgo.Transaction{Signer: owner(t).PublicKeyString(), Recipient: a.Recipient, Message: m}
+	cert, err := Verify(strings.NewReader(file), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Private || cert.Algorithm != SHA256 || cert.Owner != tx.Signer {
+		t.Fatalf("unexpected certificate %+v", cert)
+	}
+	if _, err := Verify(strings.NewReader(file+"."), tx); err == nil {
+		t.Fatal("expected an error for another file")
+	}
+}
+
+func TestPrivateApostille(t *testing.T) {
+	kp := owner(t)
+	dedicated, err := DedicatedKeyPair(kp, "contract.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := DedicatedKeyPair(kp, "contract.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dedicated.PrivateKeyString() != again.PrivateKeyString() {
+		t.Fatal("dedicated key pair should be deterministic")
+	}
+	a, err := NewPrivate(strings.NewReader(file), SHA3_512, kp, dedicated.Address(nemgo.Testnet), nemgo.Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Header + "89"; !strings.HasPrefix(a.Message().Payload, want) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, a.Message().Payload)
+	}
+	tx := nemgo.Transaction{Signer: kp.PublicKeyString(), Recipient: a.Recipient, Message: a.Message()}
+	// apostilles of multisig accounts are in the inner transaction
+	cert, err := Verify(strings.NewReader(file), nemgo.Transaction{OtherTrans: &tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cert.Private || cert.Algorithm != SHA3_512 || hex.EncodeToString(cert.Hash) != hex.EncodeToString(a.Hash) {
+		t.Fatalf("unexpected certificate %+v", cert)
+	}
+	tx.Signer = dedicated.PublicKeyString()
+	if _, err := Verify(strings.NewReader(file), tx); err == nil {
+		t.Fatal("expected an error for another owner")
+	}
+	if _, err := NewPrivate(strings.NewReader(file), SHA256, kp, "NALICE", nemgo.Testnet); err == nil {
+		t.Fatal("expected an error for an invalid dedicated account")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, m := range []nemgo.Message{
+		nemgo.NewPlainMessage("hello"),
+		{Payload: Header, Type: nemgo.MessageTypePlain},
+		{Payload: Header + "04aa", Type: nemgo.MessageTypePlain},
+		{Payload: Header + "03aa", Type: nemgo.MessageTypeEncrypted},
+	} {
+		if _, err := Parse(m); err == nil {
+			t.Fatalf("expected an error for %v", m)
+		}
+	}
+}
+
+// nisServer is a NIS holding a single apostille transaction
+func nisServer(t *testing.T, tx nemgo.Transaction) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/time-sync/network-time":
+			w.Write([]byte(`{"sendTimeStamp": 9232968000, "receiveTimeStamp": 9232968000}`))
+		case "/transaction/get":
+			if r.URL.Query().Get("hash") != apostHash {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error": "Not Found", "message": "invalid hash", "status": 404}`))
+				return
+			}
+			var pair nemgo.TransactionMetadataPair
+			pair.Meta.Height = 1234
+			pair.Meta.Hash.Data = apostHash
+			pair.Transaction = tx
+			if err := json.NewEncoder(w).Encode(pair); err != nil {
+				t.Error(err)
+			}
+		}
+	}))
+}
+
+func TestTransferAndVerifyTransaction(t *testing.T) {
+	kp := owner(t)
+	a, err := New(strings.NewReader(file), SHA3_256, nemgo.Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := nisServer(t, nemgo.Transaction{})
+	c := nemgo.New(nemgo.WithNIS(strings.TrimPrefix(s.URL, "http://"), nemgo.Testnet))
+	tx, err := a.Transfer(*c, nemgo.PublicKey(kp.PublicKeyString()))
+	s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Recipient != a.Recipient || tx.Amount != 0 || tx.Message == nil || tx.Message.Payload != a.Message().Payload {
+		t.Fatalf("unexpected transfer %+v", tx)
+	}
+	s = nisServer(t, nemgo.Transaction{Signer: kp.PublicKeyString(), Recipient: tx.Recipient, Message: *tx.Message, TimeStamp: tx.TimeStamp})
+	defer s.Close()
+	c = nemgo.New(nemgo.WithNIS(strings.TrimPrefix(s.URL, "http://"), nemgo.Testnet))
+	cert, err := VerifyTransaction(*c, strings.NewReader(file), apostHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Height != 1234 || cert.TransactionHash != apostHash || cert.TimeStamp != tx.TimeStamp {
+		t.Fatalf("unexpected certificate %+v", cert)
+	}
+	if _, err := VerifyTransaction(*c, strings.NewReader(file), "00"); err == nil {
+		t.Fatal("expected an error for an unknown transaction")
+	}
+}
@@ -56,7 +56,7 @@ func (c Client) Score() (string, error) {
 		return data.Score, err
 	}
 	if err := json.Unmarshal(body, &data); err != nil {
-		return data.Score, nil
+		return data.Score, err
 	}
 	return data.Score, nil
 }
@@ -77,7 +77,7 @@ func (c Client) Score() (string, error) {
 // time.
 type Block struct {
 	// BUG(tyler): All float64 in Block should be int
-	TimeStamp     float64
+	TimeStamp     NetworkTime
 	Signature     string
 	PrevBlockHash string
 	Type          float64
@@ -95,10 +95,11 @@ func (b *Block) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	m := t.(map[string]interface{})
-	b.TimeStamp, ok = m["timeStamp"].(float64)
+	ts, ok := m["timeStamp"].(float64)
 	if !ok {
 		return errors.New("Unable to assert timestamp to int")
 	}
+	b.TimeStamp = NetworkTime(ts)
 	b.Signature, ok = m["signature"].(string)
 	if !ok {
 		return errors.New("Unable to assert signature to string")
@@ -158,7 +159,7 @@ func (c Client) LastBlock() (Block, error) {
 		return data, err
 	}
 	if err := json.Unmarshal(body, &data); err != nil {
-		return data, nil
+		return data, err
 	}
 	return data, nil
 }
@@ -0,0 +1,140 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"filippo.io/edwards25519"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// SaltSize is the size, in bytes, of the salt prefixed to an
+	// encrypted message
+	SaltSize = 32
+	// IVSize is the size, in bytes, of the AES initialisation vector
+	// following the salt of an encrypted message
+	IVSize = aes.BlockSize
+)
+
+// SharedKey derives the 32 byte key shared between the key pair and the
+// owner of publicKey.
+// The shared point is XORed with the salt and hashed with SHA3-256, which
+// means the sender and recipient derive the same key from their own
+// private key and the other party's public key.
+func SharedKey(kp KeyPair, publicKey, salt []byte) ([]byte, error) {
+	if len(kp.Private) != PrivateKeySize {
+		return nil, errors.Errorf("private key must be %d bytes, got %d", PrivateKeySize, len(kp.Private))
+	}
+	if len(publicKey) != PublicKeySize {
+		return nil, errors.Errorf("public key must be %d bytes, got %d", PublicKeySize, len(publicKey))
+	}
+	if len(salt) != SaltSize {
+		return nil, errors.Errorf("salt must be %d bytes, got %d", SaltSize, len(salt))
+	}
+	A, err := new(edwards25519.Point).SetBytes(publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "public key is not a valid curve point")
+	}
+	shared := new(edwards25519.Point).ScalarMult(expandedScalar(kp.Private), A).Bytes()
+	for i := range shared {
+		shared[i] ^= salt[i]
+	}
+	h := sha3.NewLegacyKeccak256()
+	h.Write(shared)
+	return h.Sum(nil), nil
+}
+
+// Encrypt will encrypt msg for the owner of recipientPublicKey.
+// The result is laid out as the 32 byte salt, the 16 byte IV and then
+// the AES-256-CBC ciphertext, which is the payload NIS expects for an
+// encrypted (type 2) message.
+func Encrypt(kp KeyPair, recipientPublicKey, msg []byte) ([]byte, error) {
+	return encrypt(rand.Reader, kp, recipientPublicKey, msg)
+}
+
+func encrypt(r io.Reader, kp KeyPair, recipientPublicKey, msg []byte) ([]byte, error) {
+	out := make([]byte, SaltSize+IVSize)
+	if _, err := io.ReadFull(r, out); err != nil {
+		return nil, errors.Wrap(err, "unable to read random salt and iv")
+	}
+	salt, iv := out[:SaltSize], out[SaltSize:]
+	key, err := SharedKey(kp, recipientPublicKey, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padded := pad(msg)
+	ct := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, padded)
+	return append(out, ct...), nil
+}
+
+// Decrypt will decrypt a payload produced by Encrypt.
+// publicKey is the public key of the other party: the sender when the
+// key pair belongs to the recipient, and the recipient when the key pair
+// belongs to the sender.
+func Decrypt(kp KeyPair, publicKey, payload []byte) ([]byte, error) {
+	if len(payload) < SaltSize+IVSize+aes.BlockSize {
+		return nil, errors.New("encrypted payload is too short")
+	}
+	salt, iv, ct := payload[:SaltSize], payload[SaltSize:SaltSize+IVSize], payload[SaltSize+IVSize:]
+	if len(ct)%aes.BlockSize != 0 {
+		return nil, errors.New("encrypted payload is not a multiple of the block size")
+	}
+	key, err := SharedKey(kp, publicKey, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pt := make([]byte, len(ct))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(pt, ct)
+	return unpad(pt)
+}
+
+// pad applies PKCS#7 padding
+func pad(b []byte) []byte {
+	n := aes.BlockSize - len(b)%aes.BlockSize
+	return append(append([]byte{}, b...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// unpad removes PKCS#7 padding
+func unpad(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, errors.New("invalid padding")
+	}
+	n := int(b[len(b)-1])
+	if n == 0 || n > aes.BlockSize || n > len(b) {
+		return nil, errors.New("invalid padding, wrong key or corrupted payload")
+	}
+	for _, p := range b[len(b)-n:] {
+		if int(p) != n {
+			return nil, errors.New("invalid padding, wrong key or corrupted payload")
+		}
+	}
+	return b[:len(b)-n], nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSharedKeySymmetric(t *testing.T) {
+	alice, err := FromHex(keyVectors[0].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := FromHex(keyVectors[1].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := bytes.Repeat([]byte{0x42}, SaltSize)
+	ab, err := SharedKey(alice, bob.Public, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ba, err := SharedKey(bob, alice.Public, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ab, ba) {
+		t.Fatalf("\nWanted: %x\n   Got: %x", ab, ba)
+	}
+	if _, err := SharedKey(alice, bob.Public, salt[:31]); err == nil {
+		t.Fatal("expected an error for a short salt")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	alice, err := FromHex(keyVectors[0].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := FromHex(keyVectors[1].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"", "invoice 2018-0042", "exactly 16 bytes"} {
+		payload, err := Encrypt(alice, bob.Public, []byte(msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(payload)%16 != 0 || len(payload) <= SaltSize+IVSize {
+			t.Fatalf("unexpected payload length %d", len(payload))
+		}
+		// the recipient decrypts with the sender's public key
+		got, err := Decrypt(bob, alice.Public, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != msg {
+			t.Fatalf("\nWanted: %v\n   Got: %v", msg, string(got))
+		}
+		// the sender decrypts with the recipient's public key
+		got, err = Decrypt(alice, bob.Public, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != msg {
+			t.Fatalf("\nWanted: %v\n   Got: %v", msg, string(got))
+		}
+	}
+}
+
+func TestEncryptLayout(t *testing.T) {
+	alice, err := FromHex(keyVectors[0].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := FromHex(keyVectors[1].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := bytes.Repeat([]byte{0x07}, SaltSize+IVSize)
+	payload, err := encrypt(bytes.NewReader(random), alice, bob.Public, []byte("memo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload[:SaltSize+IVSize], random) {
+		t.Fatalf("expected payload to start with salt and iv, got %x", payload[:SaltSize+IVSize])
+	}
+	if len(payload) != SaltSize+IVSize+16 {
+		t.Fatalf("expected a single block of ciphertext, got %d bytes", len(payload)-SaltSize-IVSize)
+	}
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	alice, err := FromHex(keyVectors[0].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := FromHex(keyVectors[1].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eve, err := FromHex(keyVectors[2].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := Encrypt(alice, bob.Public, []byte("invoice 2018-0042"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Decrypt(eve, alice.Public, payload); err == nil && string(got) == "invoice 2018-0042" {
+		t.Fatal("expected decryption with the wrong key to fail")
+	}
+	if _, err := Decrypt(bob, alice.Public, payload[:40]); err == nil {
+		t.Fatal("expected an error for a truncated payload")
+	}
+}
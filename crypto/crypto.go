@@ -12,18 +12,103 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package crypto implements the NEM flavour of ed25519 key pairs along
+// with the hashing used to derive accounts from them.
 package crypto
 
-// func AccountFromPubKey(pubkey string, network nemgo.Network) (string, error) {
-// 	h := sha3.Sum256([]byte(pubkey))
-// 	r := ripemd160.New()
-// 	_, err := r.Write(h[:])
-// 	if err != nil {
-// 		return "", err
-// 	}
-// 	b := append(network, r.Sum(nil)...)
-// 	h = sha3.Sum256(b)
-// 	a := append(b, h[:4])
-// 	return a
-
-// }
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// AddressLength is the number of base32 characters in an address
+	AddressLength = 40
+	// addressSize is the number of bytes in a decoded address: the network
+	// byte, the 20 byte RIPEMD-160 hash and a 4 byte checksum
+	addressSize    = 25
+	checksumSize   = 4
+	base32Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+)
+
+// AddressFromPublicKey will derive the address of a public key on the
+// given network.
+// The public key is hashed with SHA3-256 and then RIPEMD-160, prefixed
+// with the network byte, followed by the first 4 bytes of the SHA3-256
+// hash of the result as a checksum and finally base32 encoded.
+func AddressFromPublicKey(pub []byte, network byte) (string, error) {
+	if len(pub) != PublicKeySize {
+		return "", errors.Errorf("public key must be %d bytes, got %d", PublicKeySize, len(pub))
+	}
+	h := sha3.NewLegacyKeccak256()
+	h.Write(pub)
+	r := ripemd160.New()
+	r.Write(h.Sum(nil))
+	b := append([]byte{network}, r.Sum(nil)...)
+	b = append(b, checksum(b)...)
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+// AddressFromPublicKeyHex is the same as AddressFromPublicKey but takes
+// a hex encoded public key
+func AddressFromPublicKeyHex(pub string, network byte) (string, error) {
+	b, err := hex.DecodeString(pub)
+	if err != nil {
+		return "", errors.Wrap(err, "public key is not valid hex")
+	}
+	return AddressFromPublicKey(b, network)
+}
+
+// Address returns the address of the key pair on the given network
+func (kp KeyPair) Address(network byte) string {
+	// kp.Public is always PublicKeySize bytes
+	a, _ := AddressFromPublicKey(kp.Public, network)
+	return a
+}
+
+// ValidateAddress checks the length, alphabet, network byte and checksum
+// of an address and returns an error describing the first problem found
+func ValidateAddress(address string, network byte) error {
+	if len(address) != AddressLength {
+		return errors.Errorf("address must be %d characters, got %d", AddressLength, len(address))
+	}
+	if i := strings.IndexFunc(address, func(r rune) bool {
+		return !strings.ContainsRune(base32Alphabet, r)
+	}); i >= 0 {
+		return errors.Errorf("address contains invalid character %q at position %d", address[i], i)
+	}
+	b, err := base32.StdEncoding.DecodeString(address)
+	if err != nil {
+		return errors.Wrap(err, "unable to decode address")
+	}
+	if b[0] != network {
+		return errors.Errorf("address belongs to network 0x%x, expected 0x%x", b[0], network)
+	}
+	if !bytes.Equal(checksum(b[:addressSize-checksumSize]), b[addressSize-checksumSize:]) {
+		return errors.New("address checksum does not match")
+	}
+	return nil
+}
+
+// ValidatePublicKey checks that a public key is 32 hex encoded bytes
+func ValidatePublicKey(pub string) error {
+	if len(pub) != 2*PublicKeySize {
+		return errors.Errorf("public key must be %d hex characters, got %d", 2*PublicKeySize, len(pub))
+	}
+	if _, err := hex.DecodeString(pub); err != nil {
+		return errors.Wrap(err, "public key is not valid hex")
+	}
+	return nil
+}
+
+func checksum(b []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(b)
+	return h.Sum(nil)[:checksumSize]
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import "testing"
+
+const (
+	mainnet = byte(0x68)
+	testnet = byte(0x98)
+)
+
+// Mainnet addresses for keyVectors, from the NEM test-vectors repository
+var addressVectors = []string{
+	"NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R4",
+	"NABHFGE5ORQD3LE4O6B7JUFN47ECOFBFASC3SCAC",
+	"NAVOZX4HDVOAR4W6K4WJHWPD3MOFU27DFHC7KZOZ",
+}
+
+func TestAddressFromPublicKey(t *testing.T) {
+	for i, v := range keyVectors {
+		got, err := AddressFromPublicKeyHex(v.public, mainnet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := addressVectors[i]; got != want {
+			t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
+		}
+		if err := ValidateAddress(got, mainnet); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestKeyPairAddressTestnet(t *testing.T) {
+	kp, err := FromHex(keyVectors[0].private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := kp.Address(testnet)
+	if a[0] != 'T' {
+		t.Fatalf("testnet address should start with T, got %v", a)
+	}
+	if err := ValidateAddress(a, testnet); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAddress(a, mainnet); err == nil {
+		t.Fatal("expected testnet address to be invalid on mainnet")
+	}
+}
+
+func TestValidateAddressInvalid(t *testing.T) {
+	for _, a := range []string{
+		"",
+		"NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R",
+		"NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R4A",
+		"NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R1",
+		"ndd2ct6lqliyq56kixi3entm6ek3d44p5jfxj4r4",
+		"NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R5",
+		"TDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R4",
+	} {
+		if err := ValidateAddress(a, mainnet); err == nil {
+			t.Fatalf("expected %q to be invalid", a)
+		}
+	}
+}
+
+func TestValidatePublicKey(t *testing.T) {
+	if err := ValidatePublicKey(keyVectors[0].public); err != nil {
+		t.Fatal(err)
+	}
+	for _, pk := range []string{"", "c5f54ba9", keyVectors[0].public[:63] + "x"} {
+		if err := ValidatePublicKey(pk); err == nil {
+			t.Fatalf("expected %q to be invalid", pk)
+		}
+	}
+}
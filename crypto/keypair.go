@@ -0,0 +1,125 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"filippo.io/edwards25519"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// PrivateKeySize is the size, in bytes, of a NEM private key
+	PrivateKeySize = 32
+	// PublicKeySize is the size, in bytes, of a NEM public key
+	PublicKeySize = 32
+)
+
+// KeyPair is a NEM ed25519 key pair.
+// NEM uses the ed25519 curve but hashes with Keccak-512 instead of the
+// SHA-512 used by standard ed25519, so keys generated here are not
+// interchangeable with golang.org/x/crypto/ed25519.
+type KeyPair struct {
+	// Private is the private key in the byte order it is displayed in
+	// by NanoWallet and NIS
+	Private []byte
+	// Public is the encoded curve point derived from Private
+	Public []byte
+}
+
+// NewKeyPair will generate a new random key pair
+func NewKeyPair() (KeyPair, error) {
+	return newKeyPair(rand.Reader)
+}
+
+func newKeyPair(r io.Reader) (KeyPair, error) {
+	priv := make([]byte, PrivateKeySize)
+	if _, err := io.ReadFull(r, priv); err != nil {
+		return KeyPair{}, errors.Wrap(err, "unable to read random private key")
+	}
+	return FromPrivateKey(priv)
+}
+
+// FromPrivateKey will create a key pair from a raw 32 byte private key
+func FromPrivateKey(priv []byte) (KeyPair, error) {
+	if len(priv) != PrivateKeySize {
+		return KeyPair{}, errors.Errorf("private key must be %d bytes, got %d", PrivateKeySize, len(priv))
+	}
+	kp := KeyPair{Private: make([]byte, PrivateKeySize)}
+	copy(kp.Private, priv)
+	kp.Public = publicKey(kp.Private)
+	return kp, nil
+}
+
+// FromHex will create a key pair from a hex encoded private key.
+// Both the 64 character form and the legacy 66 character form, which is
+// prefixed with "00", are accepted.
+func FromHex(privHex string) (KeyPair, error) {
+	if len(privHex) == 2*PrivateKeySize+2 && strings.HasPrefix(privHex, "00") {
+		privHex = privHex[2:]
+	}
+	if len(privHex) != 2*PrivateKeySize {
+		return KeyPair{}, errors.Errorf("private key must be %d hex characters, got %d", 2*PrivateKeySize, len(privHex))
+	}
+	priv, err := hex.DecodeString(privHex)
+	if err != nil {
+		return KeyPair{}, errors.Wrap(err, "private key is not valid hex")
+	}
+	return FromPrivateKey(priv)
+}
+
+// PrivateKeyString returns the private key hex encoded
+func (kp KeyPair) PrivateKeyString() string {
+	return hex.EncodeToString(kp.Private)
+}
+
+// PublicKeyString returns the public key hex encoded
+func (kp KeyPair) PublicKeyString() string {
+	return hex.EncodeToString(kp.Public)
+}
+
+// publicKey derives the public key for a private key
+func publicKey(priv []byte) []byte {
+	a := expandedScalar(priv)
+	return new(edwards25519.Point).ScalarBaseMult(a).Bytes()
+}
+
+// expandedHash returns the Keccak-512 hash of the private key.
+// NIS treats private keys as little endian integers, so the bytes are
+// reversed from the displayed order before hashing.
+func expandedHash(priv []byte) []byte {
+	rev := make([]byte, len(priv))
+	for i, b := range priv {
+		rev[len(priv)-1-i] = b
+	}
+	h := sha3.NewLegacyKeccak512()
+	h.Write(rev)
+	return h.Sum(nil)
+}
+
+// expandedScalar returns the clamped secret scalar for a private key
+func expandedScalar(priv []byte) *edwards25519.Scalar {
+	s, err := new(edwards25519.Scalar).SetBytesWithClamping(expandedHash(priv)[:32])
+	if err != nil {
+		// SetBytesWithClamping only fails on wrong input length
+		panic(err)
+	}
+	return s
+}
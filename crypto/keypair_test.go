@@ -0,0 +1,90 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Test vectors taken from the NEM test-vectors repository (1.test-keys.dat)
+var keyVectors = []struct {
+	private string
+	public  string
+}{
+	{"575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced",
+		"c5f54ba980fcbb657dbaaa42700539b207873e134d2375efeab5f1ab52f87844"},
+	{"5b0e3fa5d3b49a79022d7c1e121ba1cbbf4db5821f47ab8c708ef88defc29bfe",
+		"96eb2a145211b1b7ab5f0d4b14f8abc8d695c7aee31a3cfc2d4881313c68eea3"},
+	{"738ba9bb9110aea8f15caa353aca5653b4bdfca1db9f34d0efed2ce1325aeeda",
+		"2d8425e4ca2d8926346c7a7ca39826acd881a8639e81bd68820409c6e30d142a"},
+}
+
+func TestFromHex(t *testing.T) {
+	for _, v := range keyVectors {
+		kp, err := FromHex(v.private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := kp.PublicKeyString(); got != v.public {
+			t.Fatalf("\nWanted: %v\n   Got: %v", v.public, got)
+		}
+		if got := kp.PrivateKeyString(); got != v.private {
+			t.Fatalf("\nWanted: %v\n   Got: %v", v.private, got)
+		}
+	}
+}
+
+func TestFromHexLegacyPrefix(t *testing.T) {
+	v := keyVectors[0]
+	kp, err := FromHex("00" + v.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := kp.PublicKeyString(); got != v.public {
+		t.Fatalf("\nWanted: %v\n   Got: %v", v.public, got)
+	}
+}
+
+func TestFromHexInvalid(t *testing.T) {
+	for _, k := range []string{
+		"",
+		"575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704c",
+		"11575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced",
+		"z75dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced",
+	} {
+		if _, err := FromHex(k); err == nil {
+			t.Fatalf("expected an error for %q", k)
+		}
+	}
+}
+
+func TestNewKeyPair(t *testing.T) {
+	seed := bytes.Repeat([]byte{0xab}, PrivateKeySize)
+	kp, err := newKeyPair(bytes.NewReader(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := FromPrivateKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(kp.Public, want.Public) {
+		t.Fatalf("\nWanted: %x\n   Got: %x", want.Public, kp.Public)
+	}
+	if _, err := newKeyPair(bytes.NewReader(seed[:10])); err == nil {
+		t.Fatal("expected an error for a short random source")
+	}
+}
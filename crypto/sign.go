@@ -0,0 +1,93 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+
+	"filippo.io/edwards25519"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+// SignatureSize is the size, in bytes, of a NEM signature
+const SignatureSize = 64
+
+// Sign will sign data with the private key of the key pair.
+// The signature is the 32 byte encoded point R followed by the 32 byte
+// scalar S, the same layout NIS uses for the Signature field of
+// transactions and blocks.
+func (kp KeyPair) Sign(data []byte) ([]byte, error) {
+	if len(kp.Private) != PrivateKeySize {
+		return nil, errors.Errorf("private key must be %d bytes, got %d", PrivateKeySize, len(kp.Private))
+	}
+	digest := expandedHash(kp.Private)
+	a := expandedScalar(kp.Private)
+	pub := new(edwards25519.Point).ScalarBaseMult(a).Bytes()
+
+	h := sha3.NewLegacyKeccak512()
+	h.Write(digest[32:])
+	h.Write(data)
+	r := hashScalar(h.Sum(nil))
+	R := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	k := challenge(R, pub, data)
+	S := new(edwards25519.Scalar).MultiplyAdd(k, a, r)
+
+	sig := make([]byte, 0, SignatureSize)
+	sig = append(sig, R...)
+	return append(sig, S.Bytes()...), nil
+}
+
+// Verify will report whether signature is a valid signature of data
+// by publicKey.
+// Signatures with a non canonical S are rejected, as they are by NIS.
+func Verify(publicKey, data, signature []byte) bool {
+	if len(publicKey) != PublicKeySize || len(signature) != SignatureSize {
+		return false
+	}
+	A, err := new(edwards25519.Point).SetBytes(publicKey)
+	if err != nil {
+		return false
+	}
+	S, err := new(edwards25519.Scalar).SetCanonicalBytes(signature[32:])
+	if err != nil {
+		return false
+	}
+	k := challenge(signature[:32], publicKey, data)
+	k.Negate(k)
+	// R = S*B - k*A
+	R := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(k, A, S)
+	return bytes.Equal(R.Bytes(), signature[:32])
+}
+
+// challenge computes Keccak-512(R || A || data) reduced modulo the
+// group order
+func challenge(R, A, data []byte) *edwards25519.Scalar {
+	h := sha3.NewLegacyKeccak512()
+	h.Write(R)
+	h.Write(A)
+	h.Write(data)
+	return hashScalar(h.Sum(nil))
+}
+
+func hashScalar(digest []byte) *edwards25519.Scalar {
+	s, err := new(edwards25519.Scalar).SetUniformBytes(digest)
+	if err != nil {
+		// SetUniformBytes only fails on wrong input length
+		panic(err)
+	}
+	return s
+}
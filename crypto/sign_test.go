@@ -0,0 +1,103 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Test vector taken from the NEM test-vectors repository (2.test-sign.dat)
+const (
+	signPrivate   = "abf4cf55a2b3f742d7543d9cc17f50447b969e6e06f5ea9195d428ab12b7318d"
+	signPublic    = "8a558c728c21c126181e5e654b404a45b4f0137ce88177435a69978cc6bec1f4"
+	signData      = "8ce03cd60514233b86789729102ea09e867fc6d964dea8c2018ef7d0a2e0e24bf7e348e917116690b9"
+	signSignature = "d9cec0cc0e3465fab229f8e1d6db68ab9cc99a18cb0435f70deb6100948576cd5c0aa1feb550bdd8693ef81eb10a556a622db1f9301986827b96716a7134230c"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSign(t *testing.T) {
+	kp, err := FromHex(signPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := kp.PublicKeyString(); got != signPublic {
+		t.Fatalf("\nWanted: %v\n   Got: %v", signPublic, got)
+	}
+	sig, err := kp.Sign(mustHex(t, signData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(sig); got != signSignature {
+		t.Fatalf("\nWanted: %v\n   Got: %v", signSignature, got)
+	}
+}
+
+func TestSignEmptyKeyPair(t *testing.T) {
+	if _, err := (KeyPair{}).Sign([]byte("data")); err == nil {
+		t.Fatal("expected an error signing with an empty key pair")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	pub := mustHex(t, signPublic)
+	data := mustHex(t, signData)
+	sig := mustHex(t, signSignature)
+	if !Verify(pub, data, sig) {
+		t.Fatal("expected signature to verify")
+	}
+	tampered := append([]byte{}, data...)
+	tampered[0] ^= 0xff
+	if Verify(pub, tampered, sig) {
+		t.Fatal("expected signature over different data to fail")
+	}
+	badSig := append([]byte{}, sig...)
+	badSig[63] |= 0xf0
+	if Verify(pub, data, badSig) {
+		t.Fatal("expected non canonical signature to fail")
+	}
+	if Verify(pub[:31], data, sig) || Verify(pub, data, sig[:63]) {
+		t.Fatal("expected short inputs to fail")
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	kp, err := NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge := []byte("login challenge 8f14e45fceea167a")
+	sig, err := kp.Sign(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(kp.Public, challenge, sig) {
+		t.Fatal("expected signature to verify")
+	}
+	other, err := NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Verify(other.Public, challenge, sig) {
+		t.Fatal("expected signature to fail for another key")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// The NIS1 fee schedule, in micro XEM
+const (
+	// FeeUnit is the unit every fee is a multiple of, 0.05 XEM
+	FeeUnit = 50000
+	// ImportanceTransferFee is the fee of an importance transfer
+	ImportanceTransferFee = 3 * FeeUnit
+	// MultisigAggregateModificationFee is the fee of converting an
+	// account to multisig or changing its cosignatories
+	MultisigAggregateModificationFee = 10 * FeeUnit
+	// MultisigSignatureFee is the fee of a cosignatory's signature
+	MultisigSignatureFee = 3 * FeeUnit
+	// MultisigFee is the fee of wrapping a transaction on behalf of a
+	// multisig account, the inner transaction pays its own fee
+	MultisigFee = 3 * FeeUnit
+	// ProvisionNamespaceFee is the fee of provisioning a namespace, on
+	// top of its rental fee
+	ProvisionNamespaceFee = 3 * FeeUnit
+	// MosaicDefinitionCreationFee is the fee of creating a mosaic
+	// definition, on top of its creation fee
+	MosaicDefinitionCreationFee = 3 * FeeUnit
+	// MosaicSupplyChangeFee is the fee of changing the supply of a mosaic
+	MosaicSupplyChangeFee = 3 * FeeUnit
+)
+
+// MosaicSupplyFunc returns the supply, in whole units, and the
+// divisibility of a mosaic
+type MosaicSupplyFunc func(id MosaicID) (supply int64, divisibility int, err error)
+
+// Fee calculates the minimum fee NIS accepts for a transaction.
+// supply is called for every mosaic other than XEM attached to a
+// transfer, it may be nil when there are none.
+func Fee(tx TransactionEntity, supply MosaicSupplyFunc) (int64, error) {
+	switch t := tx.(type) {
+	case *TransferTransaction:
+		return transactionTransferFee(t, supply)
+	case *ImportanceTransferTransaction:
+		return ImportanceTransferFee, nil
+	case *MultisigAggregateModificationTransaction:
+		return MultisigAggregateModificationFee, nil
+	case *MultisigSignatureTransaction:
+		return MultisigSignatureFee, nil
+	case *MultisigTransaction:
+		return MultisigFee, nil
+	case *ProvisionNamespaceTransaction:
+		return ProvisionNamespaceFee, nil
+	case *MosaicDefinitionCreationTransaction:
+		return MosaicDefinitionCreationFee, nil
+	case *MosaicSupplyChangeTransaction:
+		return MosaicSupplyChangeFee, nil
+	default:
+		return 0, errors.Errorf("no fee known for transaction type 0x%x", tx.Common().Type)
+	}
+}
+
+// Fee calculates the minimum fee NIS accepts for a transaction, looking
+// up the supply and divisibility of mosaics from NIS
+func (c Client) Fee(tx TransactionEntity) (int64, error) {
+	return Fee(tx, c.mosaicSupply)
+}
+
+// mosaicSupply is a MosaicSupplyFunc using NIS
+func (c Client) mosaicSupply(id MosaicID) (int64, int, error) {
+	def, err := c.MosaicDefinition(id)
+	if err != nil {
+		return 0, 0, err
+	}
+	div, err := def.Divisibility()
+	if err != nil {
+		return 0, 0, err
+	}
+	supply, err := c.MosaicSupply(id)
+	if err != nil {
+		return 0, 0, err
+	}
+	return supply, div, nil
+}
+
+// transactionTransferFee calculates the fee of a transfer. Version 1
+// transfers and version 2 transfers without mosaics pay for the XEM
+// amount, other version 2 transfers pay for every mosaic.
+func transactionTransferFee(t *TransferTransaction, supply MosaicSupplyFunc) (int64, error) {
+	if t.TxVersion() < 2 || len(t.Mosaics) == 0 {
+		return transferFee(t.Amount, t.Message)
+	}
+	fee, err := messageFee(t.Message)
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range t.Mosaics {
+		if m.MosaicID == XEM {
+			fee += mosaicFee(m.Quantity, t.Amount, xemSupply, xemDivisibility)
+			continue
+		}
+		if supply == nil {
+			return 0, errors.Errorf("supply of %s is needed to calculate the fee", m.MosaicID)
+		}
+		s, div, err := supply(m.MosaicID)
+		if err != nil {
+			return 0, errors.Wrapf(err, "supply of %s", m.MosaicID)
+		}
+		fee += mosaicFee(m.Quantity, t.Amount, s, div)
+	}
+	return fee, nil
+}
+
+// transferFee calculates the fee of a transfer from the amount of XEM
+// sent and the length of the message
+func transferFee(amount int64, m *Message) (int64, error) {
+	fee, err := messageFee(m)
+	if err != nil {
+		return 0, err
+	}
+	return fee + FeeUnit*xemFeeUnits(amount/1000000), nil
+}
+
+// mosaicFee calculates the fee of attaching quantity of a mosaic to a
+// version 2 transfer. The quantity is first converted to the amount of
+// XEM of the same share of the XEM supply, then the fee of that amount
+// is lowered for mosaics with a small total quantity. Mosaics without
+// decimals and a supply of at most 10000 always pay a single fee unit.
+func mosaicFee(quantity, multiplier, supply int64, divisibility int) int64 {
+	if supply <= 0 || divisibility == 0 && supply <= 10000 {
+		return FeeUnit
+	}
+	xem := new(big.Int).Mul(big.NewInt(xemSupply), big.NewInt(quantity))
+	xem.Mul(xem, big.NewInt(multiplier))
+	xem.Quo(xem, big.NewInt(supply))
+	xem.Quo(xem, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(divisibility+6)), nil))
+	units := xemFeeUnits(xem.Int64())
+	total := supply
+	for i := 0; i < divisibility; i++ {
+		total *= 10
+	}
+	if ratio := maxMosaicQuantity / total; ratio > 1 {
+		units -= int64(math.Floor(0.8 * math.Log(float64(ratio))))
+	}
+	if units < 1 {
+		units = 1
+	}
+	return FeeUnit * units
+}
+
+// xemFeeUnits returns the number of fee units for sending xem whole XEM,
+// one unit for every 10000 XEM and at most 25
+func xemFeeUnits(xem int64) int64 {
+	units := xem / 10000
+	if units < 1 {
+		units = 1
+	}
+	if units > 25 {
+		units = 25
+	}
+	return units
+}
+
+// messageFee calculates the fee of attaching a message to a transfer
+func messageFee(m *Message) (int64, error) {
+	if m == nil || m.Payload == "" {
+		return 0, nil
+	}
+	payload, err := m.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	return FeeUnit * int64(len(payload)/32+1), nil
+}
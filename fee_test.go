@@ -0,0 +1,130 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFee(t *testing.T) {
+	for _, tc := range []struct {
+		tx   TransactionEntity
+		want int64
+	}{
+		{&TransferTransaction{CommonTransaction: testCommon(TransferType, 1), Amount: 30000000000}, 150000},
+		{&TransferTransaction{CommonTransaction: testCommon(TransferType, 2), Amount: 30000000000}, 150000},
+		{&ImportanceTransferTransaction{}, 150000},
+		{&MultisigAggregateModificationTransaction{}, 500000},
+		{&MultisigSignatureTransaction{}, 150000},
+		{&MultisigTransaction{}, 150000},
+		{&ProvisionNamespaceTransaction{RentalFee: 100000000}, 150000},
+		{&MosaicDefinitionCreationTransaction{CreationFee: 10000000}, 150000},
+		{&MosaicSupplyChangeTransaction{}, 150000},
+	} {
+		got, err := Fee(tc.tx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Fatalf("%T\nWanted: %v\n   Got: %v", tc.tx, tc.want, got)
+		}
+	}
+}
+
+func TestFeeMosaics(t *testing.T) {
+	tx := &TransferTransaction{
+		CommonTransaction: testCommon(TransferType, 2),
+		Amount:            mosaicMultiplier,
+		Message:           &Message{Payload: strings.Repeat("61", 40), Type: MessageTypePlain},
+		Mosaics: []Mosaic{
+			{MosaicID: MosaicID{NamespaceID: "alice.drinks", Name: "tokens"}, Quantity: 20000},
+			{MosaicID: XEM, Quantity: 50000000000}}}
+	supply := func(id MosaicID) (int64, int, error) {
+		if id.Name != "tokens" {
+			return 0, 0, errors.Errorf("unexpected lookup of %s", id)
+		}
+		return 20000, 0, nil
+	}
+	// 2 units for the message, 4 for the tokens and 5 for the XEM
+	got, err := Fee(tx, supply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 550000 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 550000, got)
+	}
+	// half the multiplier halves the XEM equivalent of every mosaic
+	tx.Amount = mosaicMultiplier / 2
+	if got, _ = Fee(tx, supply); got != 400000 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 400000, got)
+	}
+	if _, err := Fee(tx, nil); err == nil {
+		t.Fatal("expected an error without a way to look up the supply")
+	}
+	if got, err = clientMock.Fee(tx); err != nil || got != 400000 {
+		t.Fatalf("\nWanted: %v\n   Got: %v (%v)", 400000, got, err)
+	}
+}
+
+func TestTransferFee(t *testing.T) {
+	for _, tc := range []struct {
+		amount int64
+		msg    *Message
+		want   int64
+	}{
+		{0, nil, 50000},
+		{19999999999, nil, 50000},
+		{20000000000, nil, 100000},
+		{500000000000, nil, 1250000},
+		{1000000000000, nil, 1250000},
+		{1000000, &Message{Payload: strings.Repeat("61", 31), Type: MessageTypePlain}, 100000},
+		{1000000, &Message{Payload: strings.Repeat("61", 32), Type: MessageTypePlain}, 150000},
+		{1000000, &Message{Type: MessageTypePlain}, 50000},
+	} {
+		got, err := transferFee(tc.amount, tc.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Fatalf("amount %d: wanted %v, got %v", tc.amount, tc.want, got)
+		}
+	}
+}
+
+func TestMosaicFee(t *testing.T) {
+	for _, tc := range []struct {
+		quantity     int64
+		supply       int64
+		divisibility int
+		want         int64
+	}{
+		// XEM is charged like a version 1 transfer
+		{150000000000, xemSupply, xemDivisibility, 750000},
+		{1000000, xemSupply, xemDivisibility, 50000},
+		// small business mosaics pay a single unit
+		{10000, 10000, 0, 50000},
+		// the whole supply of a mosaic is worth all XEM, 25 units, less
+		// an adjustment of 21 units for its small total quantity
+		{20000, 20000, 0, 200000},
+		{1000, 1000000, 3, 50000},
+	} {
+		if got := mosaicFee(tc.quantity, mosaicMultiplier, tc.supply, tc.divisibility); got != tc.want {
+			t.Fatalf("%+v\nWanted: %v\n   Got: %v", tc, tc.want, got)
+		}
+	}
+}
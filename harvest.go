@@ -0,0 +1,319 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+// Remote harvesting statuses found in AccountMetadata.RemoteStatus
+const (
+	RemoteStatusRemote       = "REMOTE"
+	RemoteStatusActivating   = "ACTIVATING"
+	RemoteStatusActive       = "ACTIVE"
+	RemoteStatusDeactivating = "DEACTIVATING"
+	RemoteStatusInactive     = "INACTIVE"
+)
+
+// ImportanceTransferBlocks is the number of blocks after which an
+// importance transfer takes effect
+const ImportanceTransferBlocks = 360
+
+// NewRemoteKeyPair generates a key pair for a remote account and checks
+// with NIS that the account has never been used, as NIS rejects an
+// importance transfer to an account holding XEM
+func (c Client) NewRemoteKeyPair() (crypto.KeyPair, error) {
+	kp, err := crypto.NewKeyPair()
+	if err != nil {
+		return kp, err
+	}
+	acc, err := c.AccountData(Address(kp.Address(c.network)))
+	if err != nil {
+		return kp, err
+	}
+	if acc.Account.Balance != 0 || acc.Account.PublicKey != "" {
+		return kp, errors.Errorf("remote account %s is already in use", kp.Address(c.network))
+	}
+	return kp, nil
+}
+
+// NewImportanceTransfer will build an unsigned importance transfer, mode
+// being ImportanceTransferActivate or ImportanceTransferDeactivate, that
+// lets remote harvest on behalf of signer. Activation needs remote
+// harvesting to be inactive and remote to hold no XEM, deactivation needs
+// it to be active. The change takes ImportanceTransferBlocks blocks,
+// which FollowRemoteStatus can wait for.
+func (c Client) NewImportanceTransfer(signer PublicKey, remote PublicKey, mode int, opts ...TxOption) (ImportanceTransferTransaction, error) {
+	var tx ImportanceTransferTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	if err := remote.validate(c.network); err != nil {
+		return tx, errors.Wrap(err, "invalid remote account")
+	}
+	if strings.EqualFold(signer.String(), remote.String()) {
+		return tx, errors.New("an account cannot be its own remote account")
+	}
+	meta, err := c.AccountData(signer)
+	if err != nil {
+		return tx, err
+	}
+	status := meta.Meta.RemoteStatus
+	switch mode {
+	case ImportanceTransferActivate:
+		if status != RemoteStatusInactive {
+			return tx, errors.Errorf("remote harvesting must be %s to activate it, it is %s", RemoteStatusInactive, status)
+		}
+		acc, err := c.AccountData(remote)
+		if err != nil {
+			return tx, err
+		}
+		if acc.Account.Balance != 0 {
+			return tx, errors.New("remote account must not hold any XEM")
+		}
+	case ImportanceTransferDeactivate:
+		if status != RemoteStatusActive {
+			return tx, errors.Errorf("remote harvesting must be %s to deactivate it, it is %s", RemoteStatusActive, status)
+		}
+	default:
+		return tx, errors.Errorf("unknown importance transfer mode %d", mode)
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = ImportanceTransferTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      ImportanceTransferType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(signer.String()),
+			Deadline:  now.Add(o.deadline)},
+		Mode:          mode,
+		RemoteAccount: strings.ToLower(remote.String())}
+	return tx, c.setFee(&tx, o)
+}
+
+// RemoteProgress is the state of an importance transfer being followed
+type RemoteProgress struct {
+	// Status is the current remote status of the account
+	Status string
+	// Height is the current height of the chain
+	Height int
+	// BlocksLeft estimates the number of blocks until the transfer takes
+	// effect, counted from when the transitional status was first seen
+	BlocksLeft int
+}
+
+// FollowRemoteStatus polls the remote status of address every interval
+// until an importance transfer completes, going through ACTIVATING to
+// ACTIVE when activate is true and through DEACTIVATING to INACTIVE
+// otherwise. progress, if not nil, is called after every poll. It
+// returns once the final status is reached, when ctx is done, or when
+// the status goes the other way.
+func (c Client) FollowRemoteStatus(ctx context.Context, address string, activate bool, interval time.Duration, progress func(RemoteProgress)) error {
+	if interval <= 0 {
+		return errors.Errorf("interval must be positive, got %v", interval)
+	}
+	from, during, to := RemoteStatusInactive, RemoteStatusActivating, RemoteStatusActive
+	if !activate {
+		from, during, to = RemoteStatusActive, RemoteStatusDeactivating, RemoteStatusInactive
+	}
+	started := 0
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		meta, err := c.AccountStatus(address)
+		if err != nil {
+			return err
+		}
+		height, err := c.Height()
+		if err != nil {
+			return err
+		}
+		p := RemoteProgress{Status: meta.RemoteStatus, Height: height}
+		switch meta.RemoteStatus {
+		case to:
+		case during:
+			if started == 0 {
+				started = height
+			}
+			if p.BlocksLeft = started + ImportanceTransferBlocks - height; p.BlocksLeft < 1 {
+				p.BlocksLeft = 1
+			}
+		case from:
+			// the importance transfer is not confirmed yet
+			if started != 0 {
+				return errors.Errorf("remote status went back to %s", from)
+			}
+			p.BlocksLeft = ImportanceTransferBlocks
+		default:
+			return errors.Errorf("unexpected remote status %s", meta.RemoteStatus)
+		}
+		if progress != nil {
+			progress(p)
+		}
+		if meta.RemoteStatus == to {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Harvesting statuses found in AccountMetadata.Status
+const (
+	HarvestStatusUnknown  = "UNKNOWN"
+	HarvestStatusLocked   = "LOCKED"
+	HarvestStatusUnlocked = "UNLOCKED"
+)
+
+// UnlockedInfo is the number of accounts harvesting on a NIS
+type UnlockedInfo struct {
+	// NumUnlocked is the number of accounts currently unlocked
+	NumUnlocked int `json:"num-unlocked"`
+	// MaxUnlocked is the number of accounts the NIS allows to be unlocked
+	MaxUnlocked int `json:"max-unlocked"`
+}
+
+// Unlock starts harvesting on the NIS with kp, which should be the key
+// pair of a remote account with remote harvesting active. The private
+// key is only sent over https or to a NIS on the loopback interface.
+func (c Client) Unlock(kp crypto.KeyPair) error {
+	return c.sendPrivateKey("/account/unlock", kp)
+}
+
+// Lock stops harvesting on the NIS with kp
+func (c Client) Lock(kp crypto.KeyPair) error {
+	return c.sendPrivateKey("/account/lock", kp)
+}
+
+// UnlockedInfo gets the number of accounts harvesting on the NIS and how
+// many it allows
+func (c Client) UnlockedInfo() (UnlockedInfo, error) {
+	var data UnlockedInfo
+	c.url.Path = "/account/unlocked/info"
+	req, err := c.buildReq(nil, nil, http.MethodPost)
+	if err != nil {
+		return data, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// sendPrivateKey posts the private key of kp to path
+func (c Client) sendPrivateKey(path string, kp crypto.KeyPair) error {
+	if !c.secure() {
+		return errors.Errorf("refusing to send a private key to %s over %s", c.url.Host, c.url.Scheme)
+	}
+	payload, err := json.Marshal(struct {
+		Value string `json:"value"`
+	}{kp.PrivateKeyString()})
+	if err != nil {
+		return err
+	}
+	c.url.Path = path
+	req, err := c.buildReq(nil, payload, http.MethodPost)
+	if err != nil {
+		return err
+	}
+	_, err = c.request(req)
+	return err
+}
+
+// secure reports whether the NIS is reached over https or on the
+// loopback interface
+func (c Client) secure() bool {
+	if c.url.Scheme == "https" {
+		return true
+	}
+	host := c.url.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// HarvestEvent reports what SuperviseHarvesting did for a remote account
+type HarvestEvent struct {
+	// Address is the address of the remote account
+	Address string
+	// Unlocked is set when the account was found locked and unlocked
+	Unlocked bool
+	// Err is set when the status could not be read or unlocking failed
+	Err error
+}
+
+// SuperviseHarvesting keeps the remote accounts unlocked on the NIS,
+// which locks every account when it restarts. Every interval it reads
+// the status of each account and unlocks those that are LOCKED. Errors
+// do not stop it, as the NIS may be down for a while, they are reported
+// to report along with every unlock if report is not nil. It runs until
+// ctx is done.
+func (c Client) SuperviseHarvesting(ctx context.Context, remotes []crypto.KeyPair, interval time.Duration, report func(HarvestEvent)) error {
+	if !c.secure() {
+		return errors.Errorf("refusing to send private keys to %s over %s", c.url.Host, c.url.Scheme)
+	}
+	if interval <= 0 {
+		return errors.Errorf("interval must be positive, got %v", interval)
+	}
+	if report == nil {
+		report = func(HarvestEvent) {}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, kp := range remotes {
+			address := kp.Address(c.network)
+			meta, err := c.AccountStatus(address)
+			if err != nil {
+				report(HarvestEvent{Address: address, Err: errors.Wrap(err, "unable to get account status")})
+				continue
+			}
+			if meta.Status != HarvestStatusLocked {
+				continue
+			}
+			if err := c.Unlock(kp); err != nil {
+				report(HarvestEvent{Address: address, Err: errors.Wrap(err, "unable to unlock account")})
+				continue
+			}
+			report(HarvestEvent{Address: address, Unlocked: true})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
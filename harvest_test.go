@@ -0,0 +1,289 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+// remoteClient is a client where the signer has the given remote status
+// and every other account holds balance
+func remoteClient(status string, balance int) Client {
+	return Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			switch req.URL.Path {
+			case "/account/get/from-public-key", "/account/get":
+				if req.URL.Query().Get("publicKey") == testSigner {
+					return []byte(fmt.Sprintf(`{"account": {"publicKey": %q}, "meta": {"remoteStatus": %q}}`, testSigner, status)), nil
+				}
+				return []byte(fmt.Sprintf(`{"account": {"balance": %d}, "meta": {"remoteStatus": "INACTIVE"}}`, balance)), nil
+			}
+			return sendReqMock(req)
+		}}
+}
+
+func TestNewRemoteKeyPair(t *testing.T) {
+	kp, err := remoteClient(RemoteStatusInactive, 0).NewRemoteKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crypto.ValidatePublicKey(kp.PublicKeyString()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remoteClient(RemoteStatusInactive, 1).NewRemoteKeyPair(); err == nil {
+		t.Fatal("expected an error for an account in use")
+	}
+}
+
+func TestNewImportanceTransfer(t *testing.T) {
+	tx, err := remoteClient(RemoteStatusInactive, 0).NewImportanceTransfer(testSigner, PublicKey(remoteKey), ImportanceTransferActivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ImportanceTransferTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      ImportanceTransferType,
+			Version:   version(Testnet, 1),
+			TimeStamp: 9232968,
+			Signer:    testSigner,
+			Fee:       ImportanceTransferFee,
+			Deadline:  9232968 + 3600},
+		Mode:          ImportanceTransferActivate,
+		RemoteAccount: remoteKey}
+	if !reflect.DeepEqual(want, tx) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, tx)
+	}
+	tx, err = remoteClient(RemoteStatusActive, 0).NewImportanceTransfer(testSigner, PublicKey(remoteKey), ImportanceTransferDeactivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Mode != ImportanceTransferDeactivate {
+		t.Fatalf("\nWanted: %v\n   Got: %v", ImportanceTransferDeactivate, tx.Mode)
+	}
+}
+
+func TestNewImportanceTransferInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       Client
+		remote  PublicKey
+		mode    int
+		wantErr bool
+	}{
+		{"activating", remoteClient(RemoteStatusActivating, 0), PublicKey(remoteKey), ImportanceTransferActivate, true},
+		{"already active", remoteClient(RemoteStatusActive, 0), PublicKey(remoteKey), ImportanceTransferActivate, true},
+		{"already inactive", remoteClient(RemoteStatusInactive, 0), PublicKey(remoteKey), ImportanceTransferDeactivate, true},
+		{"remote has balance", remoteClient(RemoteStatusInactive, 1), PublicKey(remoteKey), ImportanceTransferActivate, true},
+		{"own remote", remoteClient(RemoteStatusInactive, 0), testSigner, ImportanceTransferActivate, true},
+		{"invalid remote", remoteClient(RemoteStatusInactive, 0), "abcd", ImportanceTransferActivate, true},
+		{"unknown mode", remoteClient(RemoteStatusInactive, 0), PublicKey(remoteKey), 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.c.NewImportanceTransfer(testSigner, tt.remote, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("\nWanted: %v\n   Got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// followClient is a client whose remote status and height change with
+// every poll of the remote status
+func followClient(statuses []string, heights []int) Client {
+	poll := -1
+	return Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			switch req.URL.Path {
+			case "/account/status":
+				if poll < len(statuses)-1 {
+					poll++
+				}
+				return []byte(fmt.Sprintf(`{"remoteStatus": %q}`, statuses[poll])), nil
+			case "/chain/height":
+				return []byte(fmt.Sprintf(`{"height": %d}`, heights[poll])), nil
+			}
+			return sendReqMock(req)
+		}}
+}
+
+func TestFollowRemoteStatus(t *testing.T) {
+	c := followClient(
+		[]string{RemoteStatusInactive, RemoteStatusActivating, RemoteStatusActivating, RemoteStatusActive},
+		[]int{100, 101, 300, 461})
+	var got []RemoteProgress
+	err := c.FollowRemoteStatus(context.Background(), "TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S", true, time.Millisecond, func(p RemoteProgress) {
+		got = append(got, p)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RemoteProgress{
+		{RemoteStatusInactive, 100, ImportanceTransferBlocks},
+		{RemoteStatusActivating, 101, 360},
+		{RemoteStatusActivating, 300, 161},
+		{RemoteStatusActive, 461, 0}}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
+	}
+}
+
+func TestFollowRemoteStatusReverted(t *testing.T) {
+	c := followClient(
+		[]string{RemoteStatusDeactivating, RemoteStatusActive},
+		[]int{100, 101})
+	err := c.FollowRemoteStatus(context.Background(), "TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S", false, time.Millisecond, nil)
+	if err == nil {
+		t.Fatal("expected an error when the status goes back")
+	}
+}
+
+func TestFollowRemoteStatusCancel(t *testing.T) {
+	c := followClient([]string{RemoteStatusActivating}, []int{100})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.FollowRemoteStatus(ctx, "TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S", true, time.Hour, nil)
+	if err != context.Canceled {
+		t.Fatalf("\nWanted: %v\n   Got: %v", context.Canceled, err)
+	}
+	if err := c.FollowRemoteStatus(context.Background(), "TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S", true, 0, nil); err == nil {
+		t.Fatal("expected an error for a zero interval")
+	}
+}
+
+// harvestClient is a local client where the accounts have the given
+// harvesting statuses, it records the private keys sent to unlock
+func harvestClient(statuses map[string]string, unlocked *[]string) Client {
+	return Client{
+		network: Testnet,
+		url:     url.URL{Scheme: "http", Host: "127.0.0.1:7890"},
+		request: func(req *http.Request) ([]byte, error) {
+			switch req.URL.Path {
+			case "/account/status":
+				status, ok := statuses[req.URL.Query().Get("address")]
+				if !ok {
+					return nil, fmt.Errorf("connection refused")
+				}
+				return []byte(fmt.Sprintf(`{"status": %q}`, status)), nil
+			case "/account/unlock":
+				var key struct{ Value string }
+				if err := json.NewDecoder(req.Body).Decode(&key); err != nil {
+					return nil, err
+				}
+				if key.Value == "" {
+					return nil, &NISError{Status: 400, Reason: "Bad Request", Message: "invalid private key"}
+				}
+				*unlocked = append(*unlocked, key.Value)
+				return nil, nil
+			case "/account/lock":
+				return nil, nil
+			case "/account/unlocked/info":
+				return []byte(`{"num-unlocked": 1, "max-unlocked": 4}`), nil
+			}
+			return sendReqMock(req)
+		}}
+}
+
+func TestUnlock(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unlocked []string
+	c := harvestClient(nil, &unlocked)
+	if err := c.Unlock(kp); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{kp.PrivateKeyString()}; !reflect.DeepEqual(want, unlocked) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, unlocked)
+	}
+	if err := c.Lock(kp); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Unlock(crypto.KeyPair{}); err == nil {
+		t.Fatal("expected the error returned by NIS")
+	}
+	c.url = url.URL{Scheme: "http", Host: "23.228.67.85:7890"}
+	if err := c.Unlock(kp); err == nil {
+		t.Fatal("expected an error sending a private key over http")
+	}
+	c.url.Scheme = "https"
+	if err := c.Unlock(kp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnlockedInfo(t *testing.T) {
+	want := UnlockedInfo{NumUnlocked: 1, MaxUnlocked: 4}
+	got, err := harvestClient(nil, nil).UnlockedInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want != got {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
+	}
+}
+
+func TestSuperviseHarvesting(t *testing.T) {
+	var remotes []crypto.KeyPair
+	for i := 0; i < 3; i++ {
+		kp, err := crypto.NewKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		remotes = append(remotes, kp)
+	}
+	statuses := map[string]string{
+		remotes[0].Address(Testnet): HarvestStatusLocked,
+		remotes[1].Address(Testnet): HarvestStatusUnlocked}
+	var unlocked []string
+	c := harvestClient(statuses, &unlocked)
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []HarvestEvent
+	err := c.SuperviseHarvesting(ctx, remotes, time.Hour, func(e HarvestEvent) {
+		got = append(got, e)
+		if len(got) == 2 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf("\nWanted: %v\n   Got: %v", context.Canceled, err)
+	}
+	if len(got) != 2 || !got[0].Unlocked || got[0].Address != remotes[0].Address(Testnet) ||
+		got[1].Err == nil || got[1].Address != remotes[2].Address(Testnet) {
+		t.Fatalf("unexpected events %v", got)
+	}
+	if want := []string{remotes[0].PrivateKeyString()}; !reflect.DeepEqual(want, unlocked) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, unlocked)
+	}
+	if err := c.SuperviseHarvesting(context.Background(), remotes, -time.Second, nil); err == nil {
+		t.Fatal("expected an error for a negative interval")
+	}
+	c.url.Host = "23.228.67.85:7890"
+	if err := c.SuperviseHarvesting(context.Background(), remotes, time.Hour, nil); err == nil {
+		t.Fatal("expected an error sending private keys over http")
+	}
+}
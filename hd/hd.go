@@ -0,0 +1,207 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hd implements hierarchical deterministic NEM accounts.
+// A BIP39 mnemonic is turned into a seed, from which keys are derived
+// following SLIP-0010 for ed25519 along NEM's BIP44 coin type, 43.
+// Every account can be recomputed offline from the mnemonic alone.
+package hd
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// CoinType is the BIP44 coin type registered for NEM
+	CoinType = 43
+	// HardenedOffset is added to an index to make it hardened.
+	// SLIP-0010 only supports hardened derivation for ed25519.
+	HardenedOffset = uint32(0x80000000)
+
+	// seedKey is the HMAC key SLIP-0010 uses to create the master key
+	// for the ed25519 curve
+	seedKey = "ed25519 seed"
+)
+
+// NewMnemonic generates a random BIP39 mnemonic from bits of entropy.
+// bits must be a multiple of 32 between 128 and 256, 256 giving a 24
+// word mnemonic.
+func NewMnemonic(bits int) (string, error) {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return "", errors.Errorf("entropy must be a multiple of 32 bits between 128 and 256, got %d", bits)
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return mnemonic(entropy), nil
+}
+
+// mnemonic encodes entropy followed by its checksum, the first bit of its
+// sha256 for every 32 bits, as words of 11 bits
+func mnemonic(entropy []byte) string {
+	sum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), sum[0])
+	words := make([]string, (len(entropy)*8+len(entropy)/4)/11)
+	for i := range words {
+		index := 0
+		for b := i * 11; b < (i+1)*11; b++ {
+			index = index<<1 | int(data[b/8]>>(7-uint(b%8))&1)
+		}
+		words[i] = wordlist[index]
+	}
+	return strings.Join(words, " ")
+}
+
+// Seed validates a BIP39 mnemonic and returns the 64 byte seed for it.
+// The passphrase is the optional BIP39 passphrase, not a wallet password.
+func Seed(mnemonic, passphrase string) ([]byte, error) {
+	if err := validateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
+
+// validateMnemonic checks that every word of mnemonic is in the word list
+// and that the checksum matches the entropy
+func validateMnemonic(m string) error {
+	words := strings.Split(m, " ")
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return errors.Errorf("invalid mnemonic, it has %d words", len(words))
+	}
+	data := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		index := wordIndex(w)
+		if index < 0 {
+			return errors.Errorf("invalid mnemonic, unknown word %q", w)
+		}
+		for b := 0; b < 11; b++ {
+			if index>>(10-uint(b))&1 == 1 {
+				bit := i*11 + b
+				data[bit/8] |= 1 << (7 - uint(bit%8))
+			}
+		}
+	}
+	entropy := data[:len(words)*4/3]
+	if mnemonic(entropy) != m {
+		return errors.New("invalid mnemonic checksum")
+	}
+	return nil
+}
+
+// wordIndex returns the position of w in the word list or -1
+func wordIndex(w string) int {
+	lo, hi := 0, len(wordlist)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case wordlist[mid] == w:
+			return mid
+		case wordlist[mid] < w:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return -1
+}
+
+// Key is an extended private key
+type Key struct {
+	Key       []byte
+	ChainCode []byte
+}
+
+// NewMaster creates the master key for a seed
+func NewMaster(seed []byte) (Key, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return Key{}, errors.Errorf("seed must be between 16 and 64 bytes, got %d", len(seed))
+	}
+	mac := hmac.New(sha512.New, []byte(seedKey))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	return Key{Key: sum[:32], ChainCode: sum[32:]}, nil
+}
+
+// Child derives the hardened child key at index.
+// The hardened offset is added to index when missing.
+func (k Key) Child(index uint32) (Key, error) {
+	if len(k.Key) != 32 || len(k.ChainCode) != 32 {
+		return Key{}, errors.New("invalid extended key")
+	}
+	index |= HardenedOffset
+	data := make([]byte, 37)
+	copy(data[1:], k.Key)
+	binary.BigEndian.PutUint32(data[33:], index)
+	mac := hmac.New(sha512.New, k.ChainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return Key{Key: sum[:32], ChainCode: sum[32:]}, nil
+}
+
+// Derive follows a path such as "m/44'/43'/0'/0'/0'" from the key.
+// Every level is hardened, whether or not it is marked with ' or H.
+func (k Key) Derive(path string) (Key, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return Key{}, errors.Errorf("path %q must start with m", path)
+	}
+	for _, p := range parts[1:] {
+		p = strings.TrimRight(p, "'hH")
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || uint32(i) >= HardenedOffset {
+			return Key{}, errors.Errorf("invalid path element %q in %q", p, path)
+		}
+		if k, err = k.Child(uint32(i)); err != nil {
+			return Key{}, err
+		}
+	}
+	return k, nil
+}
+
+// KeyPair returns the NEM key pair for the key
+func (k Key) KeyPair() (crypto.KeyPair, error) {
+	return crypto.FromPrivateKey(k.Key)
+}
+
+// AccountPath returns the derivation path of a NEM account,
+// m/44'/43'/account'/0'/0'
+func AccountPath(account uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0'/0'", CoinType, account)
+}
+
+// Account derives the key pair of the NEM account at index account
+// from a seed
+func Account(seed []byte, account uint32) (crypto.KeyPair, error) {
+	m, err := NewMaster(seed)
+	if err != nil {
+		return crypto.KeyPair{}, err
+	}
+	k, err := m.Derive(AccountPath(account))
+	if err != nil {
+		return crypto.KeyPair{}, err
+	}
+	return k.KeyPair()
+}
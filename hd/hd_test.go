@@ -0,0 +1,180 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// SLIP-0010 test vector 1 for ed25519
+func TestDeriveSLIP10(t *testing.T) {
+	m, err := NewMaster(mustHex(t, "000102030405060708090a0b0c0d0e0f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		path      string
+		chainCode string
+		key       string
+	}{
+		{"m",
+			"90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			"2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'",
+			"8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			"68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{"m/0H/1H",
+			"a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+			"b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+	} {
+		k, err := m.Derive(tc.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(k.ChainCode); got != tc.chainCode {
+			t.Fatalf("%s chain code\nWanted: %v\n   Got: %v", tc.path, tc.chainCode, got)
+		}
+		if got := hex.EncodeToString(k.Key); got != tc.key {
+			t.Fatalf("%s key\nWanted: %v\n   Got: %v", tc.path, tc.key, got)
+		}
+	}
+}
+
+// BIP39 test vector from the reference implementation
+func TestSeed(t *testing.T) {
+	mnemonic := strings.Repeat("abandon ", 11) + "about"
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	seed, err := Seed(mnemonic, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(seed); got != want {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
+	}
+	if _, err := Seed(strings.Repeat("abandon ", 12), ""); err == nil {
+		t.Fatal("expected an error for a mnemonic with a bad checksum")
+	}
+}
+
+// BIP39 test vectors from the reference implementation
+func TestMnemonic(t *testing.T) {
+	for _, tc := range []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{"00000000000000000000000000000000", strings.Repeat("abandon ", 11) + "about"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{"80808080808080808080808080808080", "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+		{"ffffffffffffffffffffffffffffffff", strings.Repeat("zoo ", 11) + "wrong"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", strings.Repeat("abandon ", 23) + "art"},
+	} {
+		if got := mnemonic(mustHex(t, tc.entropy)); got != tc.mnemonic {
+			t.Fatalf("\nWanted: %v\n   Got: %v", tc.mnemonic, got)
+		}
+		if err := validateMnemonic(tc.mnemonic); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, m := range []string{
+		strings.Repeat("abandon ", 11) + "abandon",
+		strings.Repeat("abandon ", 11) + "notaword",
+		strings.Repeat("abandon ", 10) + "about",
+	} {
+		if err := validateMnemonic(m); err == nil {
+			t.Fatalf("expected an error for %q", m)
+		}
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Fields(mnemonic)); n != 24 {
+		t.Fatalf("expected 24 words, got %d", n)
+	}
+	if _, err := Seed(mnemonic, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewMnemonic(100); err == nil {
+		t.Fatal("expected an error for an invalid entropy size")
+	}
+}
+
+func TestAccount(t *testing.T) {
+	seed, err := Seed(strings.Repeat("abandon ", 11)+"about", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if AccountPath(7) != "m/44'/43'/7'/0'/0'" {
+		t.Fatalf("unexpected path %v", AccountPath(7))
+	}
+	first, err := Account(seed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Account(seed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Private, again.Private) {
+		t.Fatal("expected derivation to be deterministic")
+	}
+	second, err := Account(seed, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first.Public, second.Public) {
+		t.Fatal("expected different accounts to have different keys")
+	}
+	m, err := NewMaster(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := m.Derive("m/44'/43'/0'/0'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k.Key, first.Private) {
+		t.Fatal("expected Account to follow the NEM path")
+	}
+}
+
+func TestDeriveInvalid(t *testing.T) {
+	m, err := NewMaster(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"", "44'/43'", "m/x'", "m/2147483648'", "m//0'"} {
+		if _, err := m.Derive(p); err == nil {
+			t.Fatalf("expected an error for %q", p)
+		}
+	}
+	if _, err := NewMaster([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected an error for a short seed")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MessageTypePlain is the message type of an unencrypted message
+	MessageTypePlain = 1
+	// MessageTypeEncrypted is the message type of a message encrypted
+	// for the recipient
+	MessageTypeEncrypted = 2
+)
+
+// hexMessagePrefix marks a plain message holding raw bytes rather than
+// text. 0xfe can never start a UTF-8 sequence, which is why NanoWallet
+// uses it to tell the two apart.
+const hexMessagePrefix = "fe"
+
+// Message is the message attached to a transfer transaction.
+// Payload is always hex encoded, as it is sent to and received from NIS.
+type Message struct {
+	Payload string
+	Type    int
+}
+
+// NewPlainMessage creates an unencrypted message holding text
+func NewPlainMessage(text string) Message {
+	return Message{Payload: hex.EncodeToString([]byte(text)), Type: MessageTypePlain}
+}
+
+// NewHexMessage creates an unencrypted message holding raw bytes given as
+// hex. The payload is prefixed with "fe" the same way NanoWallet does it
+// so readers know not to treat it as text.
+func NewHexMessage(data string) (Message, error) {
+	data = strings.ToLower(data)
+	if _, err := hex.DecodeString(data); err != nil {
+		return Message{}, errors.Wrap(err, "message is not valid hex")
+	}
+	return Message{Payload: hexMessagePrefix + data, Type: MessageTypePlain}, nil
+}
+
+// NewHexMessagePayload creates a hex message from a payload that already
+// starts with the "fe" marker, such as one copied from NanoWallet
+func NewHexMessagePayload(payload string) (Message, error) {
+	payload = strings.ToLower(payload)
+	if !strings.HasPrefix(payload, hexMessagePrefix) {
+		return Message{}, errors.Errorf("hex message payload must start with %s", hexMessagePrefix)
+	}
+	return NewHexMessage(payload[len(hexMessagePrefix):])
+}
+
+// NewEncryptedMessage creates a message holding text encrypted for the
+// owner of recipientPublicKey
+func NewEncryptedMessage(kp crypto.KeyPair, recipientPublicKey PublicKey, text string) (Message, error) {
+	pub, err := hex.DecodeString(recipientPublicKey.String())
+	if err != nil {
+		return Message{}, errors.Wrap(err, "recipient public key is not valid hex")
+	}
+	payload, err := crypto.Encrypt(kp, pub, []byte(text))
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Payload: hex.EncodeToString(payload), Type: MessageTypeEncrypted}, nil
+}
+
+// Bytes returns the decoded payload.
+// For encrypted messages this is the ciphertext, use Decrypt to read it.
+func (m Message) Bytes() ([]byte, error) {
+	b, err := hex.DecodeString(m.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "message payload is not valid hex")
+	}
+	return b, nil
+}
+
+// IsEncrypted reports whether the message is encrypted
+func (m Message) IsEncrypted() bool {
+	return m.Type == MessageTypeEncrypted
+}
+
+// IsHex reports whether the message is a plain message holding raw
+// bytes rather than text
+func (m Message) IsHex() bool {
+	return m.Type == MessageTypePlain && strings.HasPrefix(strings.ToLower(m.Payload), hexMessagePrefix)
+}
+
+// Hex returns the hex encoded data of the message without the "fe"
+// marker used by hex messages
+func (m Message) Hex() string {
+	if m.IsHex() {
+		return strings.ToLower(m.Payload[len(hexMessagePrefix):])
+	}
+	return strings.ToLower(m.Payload)
+}
+
+// Text returns the decoded text of a plain message.
+// An error is returned if the message is encrypted, holds raw bytes or
+// is not valid UTF-8.
+func (m Message) Text() (string, error) {
+	if m.IsEncrypted() {
+		return "", errors.New("message is encrypted")
+	}
+	if m.IsHex() {
+		return "", errors.New("message holds raw bytes, not text")
+	}
+	b, err := m.Bytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("message is not valid UTF-8")
+	}
+	return string(b), nil
+}
+
+// Decrypt will decrypt an encrypted message.
+// publicKey is the public key of the other party of the transaction.
+func (m Message) Decrypt(kp crypto.KeyPair, publicKey PublicKey) (string, error) {
+	if !m.IsEncrypted() {
+		return "", errors.Errorf("message type %d is not encrypted", m.Type)
+	}
+	pub, err := hex.DecodeString(publicKey.String())
+	if err != nil {
+		return "", errors.Wrap(err, "public key is not valid hex")
+	}
+	payload, err := m.Bytes()
+	if err != nil {
+		return "", err
+	}
+	b, err := crypto.Decrypt(kp, pub, payload)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("decrypted message is not valid UTF-8")
+	}
+	return string(b), nil
+}
+
+// String returns the text of a message if it has any and the hex
+// encoded payload otherwise
+func (m Message) String() string {
+	if t, err := m.Text(); err == nil {
+		return t
+	}
+	return m.Hex()
+}
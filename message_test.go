@@ -0,0 +1,127 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"testing"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+func TestPlainMessage(t *testing.T) {
+	m := Message{Payload: "526f6262657279212121", Type: MessageTypePlain}
+	got, err := m.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Robbery!!!"; got != want {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
+	}
+	if want := NewPlainMessage("Robbery!!!"); want != m {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, m)
+	}
+	u := NewPlainMessage("ref: Zahlung für Rechnung №42")
+	if got, err := u.Text(); err != nil || got != "ref: Zahlung für Rechnung №42" {
+		t.Fatalf("unexpected text %q, %v", got, err)
+	}
+}
+
+func TestHexMessage(t *testing.T) {
+	m, err := NewHexMessage("DEADBEEF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Payload != "fedeadbeef" || m.Type != MessageTypePlain {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if !m.IsHex() {
+		t.Fatal("expected a hex message")
+	}
+	if got := m.Hex(); got != "deadbeef" {
+		t.Fatalf("\nWanted: %v\n   Got: %v", "deadbeef", got)
+	}
+	if _, err := m.Text(); err == nil {
+		t.Fatal("expected an error reading a hex message as text")
+	}
+	if got := m.String(); got != "deadbeef" {
+		t.Fatalf("\nWanted: %v\n   Got: %v", "deadbeef", got)
+	}
+	// data starting with 0xfe is kept as it is
+	f, err := NewHexMessage("FE01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Payload != "fefe01" || f.Hex() != "fe01" {
+		t.Fatalf("unexpected message %+v", f)
+	}
+	if _, err := NewHexMessage("xyz"); err == nil {
+		t.Fatal("expected an error for invalid hex")
+	}
+}
+
+func TestHexMessagePayload(t *testing.T) {
+	m, err := NewHexMessagePayload("FEDEADBEEF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Message{Payload: "fedeadbeef", Type: MessageTypePlain}); m != want {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, m)
+	}
+	for _, p := range []string{"deadbeef", "fexyz", ""} {
+		if _, err := NewHexMessagePayload(p); err == nil {
+			t.Fatalf("expected an error for %q", p)
+		}
+	}
+}
+
+func TestMessageInvalidText(t *testing.T) {
+	for _, m := range []Message{
+		{Payload: "c328", Type: MessageTypePlain},
+		{Payload: "zz", Type: MessageTypePlain},
+		{Payload: "526f6262657279212121", Type: MessageTypeEncrypted},
+	} {
+		if _, err := m.Text(); err == nil {
+			t.Fatalf("expected an error for %+v", m)
+		}
+	}
+}
+
+func TestEncryptedMessage(t *testing.T) {
+	sender, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := crypto.FromHex("5b0e3fa5d3b49a79022d7c1e121ba1cbbf4db5821f47ab8c708ef88defc29bfe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewEncryptedMessage(sender, PublicKey(recipient.PublicKeyString()), "INV-2018-0042")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsEncrypted() || m.IsHex() {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	got, err := m.Decrypt(recipient, PublicKey(sender.PublicKeyString()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "INV-2018-0042" {
+		t.Fatalf("\nWanted: %v\n   Got: %v", "INV-2018-0042", got)
+	}
+	if _, err := NewPlainMessage("x").Decrypt(recipient, PublicKey(sender.PublicKeyString())); err == nil {
+		t.Fatal("expected an error decrypting a plain message")
+	}
+}
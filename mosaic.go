@@ -0,0 +1,399 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+// XEM is the id of the native mosaic of NEM
+var XEM = MosaicID{NamespaceID: "nem", Name: "xem"}
+
+const (
+	// xemSupply is the supply of XEM in whole units
+	xemSupply = 8999999999
+	// xemDivisibility is the number of decimal places of XEM
+	xemDivisibility = 6
+	// mosaicPageSize is the largest page of mosaic definitions NIS returns
+	mosaicPageSize = 100
+
+	// MosaicCreationFee is the fee paid to the mosaic sink for creating a
+	// mosaic definition, in micro XEM
+	MosaicCreationFee = 10000000
+	// MaxMosaicDivisibility is the largest number of decimal places of a
+	// mosaic
+	MaxMosaicDivisibility = 6
+	// MaxMosaicSupply is the largest supply of a mosaic in whole units
+	MaxMosaicSupply = 9000000000
+	// MaxMosaicDescriptionLen is the longest description of a mosaic
+	MaxMosaicDescriptionLen = 512
+
+	maxMosaicNameLen  = 32
+	mainnetMosaicSink = "NBMOSAICOD4F54EE5CDMR23CCBGOAM2XSIUX6TRS"
+	testnetMosaicSink = "TBMOSAICOD4F54EE5CDMR23CCBGOAM2XSJBR5OLC"
+)
+
+var mosaicName = regexp.MustCompile(`^[a-z0-9][a-z0-9 '_-]*$`)
+
+// ParseMosaicID parses a fully qualified mosaic name such as
+// "alice.drinks:orange_juice"
+func ParseMosaicID(s string) (MosaicID, error) {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 || i == len(s)-1 {
+		return MosaicID{}, errors.Errorf("mosaic %q must be of the form namespace:name", s)
+	}
+	return MosaicID{NamespaceID: s[:i], Name: s[i+1:]}, nil
+}
+
+// MosaicDefinitionMetadataPair is a mosaic definition and its metadata
+type MosaicDefinitionMetadataPair struct {
+	Meta struct {
+		ID int
+	}
+	Mosaic MosaicDefinition
+}
+
+// Property returns the value of a property of the mosaic definition, or
+// an empty string if the property is not set
+func (d MosaicDefinition) Property(name string) string {
+	for _, p := range d.Properties {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// Divisibility returns the number of decimal places of the mosaic
+func (d MosaicDefinition) Divisibility() (int, error) {
+	v := d.Property("divisibility")
+	if v == "" {
+		return 0, nil
+	}
+	div, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid divisibility of %s", d.ID)
+	}
+	return div, nil
+}
+
+// MosaicDefinitions will get a page of the mosaic definitions created in
+// a namespace. id is the metadata id of the last definition of the
+// previous page, 0 for the first page.
+func (c Client) MosaicDefinitions(namespace string, id int, pageSize int) ([]MosaicDefinitionMetadataPair, error) {
+	var data struct {
+		Data []MosaicDefinitionMetadataPair
+	}
+	params := map[string]string{"namespace": namespace, "pagesize": strconv.Itoa(pageSize)}
+	if id != 0 {
+		params["id"] = strconv.Itoa(id)
+	}
+	c.url.Path = "/namespace/mosaic/definition/page"
+	req, err := c.buildReq(params, nil, http.MethodGet)
+	if err != nil {
+		return data.Data, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return data.Data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data.Data, err
+	}
+	// NIS sends an empty object for mosaics without a levy
+	for i := range data.Data {
+		if l := data.Data[i].Mosaic.Levy; l != nil && l.Type == 0 {
+			data.Data[i].Mosaic.Levy = nil
+		}
+	}
+	return data.Data, nil
+}
+
+// MosaicDefinition will find the definition of a mosaic by going through
+// the definitions of its namespace
+func (c Client) MosaicDefinition(id MosaicID) (MosaicDefinition, error) {
+	last := 0
+	for {
+		page, err := c.MosaicDefinitions(id.NamespaceID, last, mosaicPageSize)
+		if err != nil {
+			return MosaicDefinition{}, err
+		}
+		for _, p := range page {
+			if p.Mosaic.ID == id {
+				return p.Mosaic, nil
+			}
+		}
+		if len(page) < mosaicPageSize {
+			return MosaicDefinition{}, errors.Errorf("mosaic %s not found", id)
+		}
+		last = page[len(page)-1].Meta.ID
+	}
+}
+
+// MosaicSupply will get the current supply of a mosaic in whole units
+func (c Client) MosaicSupply(id MosaicID) (int64, error) {
+	var data struct {
+		MosaicID MosaicID
+		Supply   int64
+	}
+	c.url.Path = "/mosaic/supply"
+	req, err := c.buildReq(map[string]string{"mosaicId": id.String()}, nil, http.MethodGet)
+	if err != nil {
+		return 0, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return 0, err
+	}
+	return data.Supply, nil
+}
+
+// MosaicSink returns the address mosaic creation fees are paid to on a
+// network
+func MosaicSink(network byte) (string, error) {
+	switch network {
+	case Mainnet:
+		return mainnetMosaicSink, nil
+	case Testnet:
+		return testnetMosaicSink, nil
+	}
+	return "", errors.Errorf("unknown network 0x%x", network)
+}
+
+// MosaicProperties are the properties of a new mosaic
+type MosaicProperties struct {
+	// Divisibility is the number of decimal places, at most 6
+	Divisibility int
+	// InitialSupply is the supply in whole units
+	InitialSupply int64
+	// SupplyMutable allows the creator to change the supply later
+	SupplyMutable bool
+	// Transferable allows accounts other than the creator to transfer
+	// the mosaic
+	Transferable bool
+}
+
+// Properties returns the properties as they appear in a mosaic
+// definition
+func (p MosaicProperties) Properties() []MosaicProperty {
+	return []MosaicProperty{
+		{Name: "divisibility", Value: strconv.Itoa(p.Divisibility)},
+		{Name: "initialSupply", Value: strconv.FormatInt(p.InitialSupply, 10)},
+		{Name: "supplyMutable", Value: strconv.FormatBool(p.SupplyMutable)},
+		{Name: "transferable", Value: strconv.FormatBool(p.Transferable)}}
+}
+
+// ValidateMosaicDefinition checks a mosaic definition against the NIS1
+// limits: the mosaic name, a description of at most 512 characters, a
+// divisibility of at most 6, a supply of at most 9 billion units and a
+// valid levy.
+func ValidateMosaicDefinition(d MosaicDefinition, network byte) error {
+	if err := ValidateNamespace(d.ID.NamespaceID); err != nil {
+		return err
+	}
+	if !mosaicName.MatchString(d.ID.Name) || len(d.ID.Name) > maxMosaicNameLen {
+		return errors.Errorf("invalid mosaic name %q", d.ID.Name)
+	}
+	if err := crypto.ValidatePublicKey(d.Creator); err != nil {
+		return errors.Wrap(err, "invalid creator")
+	}
+	if len(d.Description) > MaxMosaicDescriptionLen {
+		return errors.Errorf("description is longer than %d characters", MaxMosaicDescriptionLen)
+	}
+	div, err := d.Divisibility()
+	if err != nil {
+		return err
+	}
+	if div < 0 || div > MaxMosaicDivisibility {
+		return errors.Errorf("divisibility must be between 0 and %d, got %d", MaxMosaicDivisibility, div)
+	}
+	supply, err := strconv.ParseInt(d.Property("initialSupply"), 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid initial supply of %s", d.ID)
+	}
+	if err := validateSupply(supply); err != nil {
+		return err
+	}
+	for _, name := range []string{"supplyMutable", "transferable"} {
+		if _, err := strconv.ParseBool(d.Property(name)); err != nil {
+			return errors.Wrapf(err, "invalid %s of %s", name, d.ID)
+		}
+	}
+	if l := d.Levy; l != nil {
+		if l.Type != MosaicLevyAbsolute && l.Type != MosaicLevyPercentile {
+			return errors.Errorf("unknown levy type %d", l.Type)
+		}
+		if err := crypto.ValidateAddress(l.Recipient, network); err != nil {
+			return errors.Wrap(err, "invalid levy recipient")
+		}
+		if l.MosaicID.NamespaceID == "" || l.MosaicID.Name == "" {
+			return errors.New("levy has no mosaic")
+		}
+		if l.Fee <= 0 {
+			return errors.New("levy fee must be positive")
+		}
+	}
+	return nil
+}
+
+// validateSupply checks a supply in whole units of a mosaic. With at
+// most 6 decimals the supply in the smallest unit is then at most 9e15.
+func validateSupply(supply int64) error {
+	if supply < 0 || supply > MaxMosaicSupply {
+		return errors.Errorf("supply must be between 0 and %d, got %d", int64(MaxMosaicSupply), supply)
+	}
+	return nil
+}
+
+// NewMosaicDefinition will build an unsigned transaction creating the
+// mosaic id for creator, who has to own its namespace. levy may be nil.
+// The creation fee is paid to the mosaic sink of the network.
+func (c Client) NewMosaicDefinition(creator PublicKey, id MosaicID, description string, props MosaicProperties, levy *MosaicLevy, opts ...TxOption) (MosaicDefinitionCreationTransaction, error) {
+	var tx MosaicDefinitionCreationTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	def := MosaicDefinition{
+		Creator:     strings.ToLower(creator.String()),
+		ID:          id,
+		Description: description,
+		Properties:  props.Properties(),
+		Levy:        levy}
+	if err := ValidateMosaicDefinition(def, c.network); err != nil {
+		return tx, err
+	}
+	if err := c.checkNamespaceOwner(creator, id.NamespaceID); err != nil {
+		return tx, err
+	}
+	sink, err := MosaicSink(c.network)
+	if err != nil {
+		return tx, err
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = MosaicDefinitionCreationTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      MosaicDefinitionCreationType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(creator.String()),
+			Deadline:  now.Add(o.deadline)},
+		MosaicDefinition: def,
+		CreationFeeSink:  sink,
+		CreationFee:      MosaicCreationFee}
+	return tx, c.setFee(&tx, o)
+}
+
+// NewMosaicSupplyChange will build an unsigned transaction increasing or
+// decreasing, depending on supplyType, the supply of a mosaic by delta
+// whole units. Only the creator can change the supply, of a mosaic
+// without a mutable supply only while owning all of it, and a decrease
+// cannot exceed what the creator owns.
+func (c Client) NewMosaicSupplyChange(creator PublicKey, id MosaicID, supplyType int, delta int64, opts ...TxOption) (MosaicSupplyChangeTransaction, error) {
+	var tx MosaicSupplyChangeTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	if supplyType != MosaicSupplyIncrease && supplyType != MosaicSupplyDecrease {
+		return tx, errors.Errorf("unknown supply type %d", supplyType)
+	}
+	if delta <= 0 {
+		return tx, errors.New("supply change must be positive")
+	}
+	def, err := c.MosaicDefinition(id)
+	if err != nil {
+		return tx, err
+	}
+	if !strings.EqualFold(def.Creator, creator.String()) {
+		return tx, errors.Errorf("%s is not the creator of %s", creator, id)
+	}
+	div, err := def.Divisibility()
+	if err != nil {
+		return tx, err
+	}
+	supply, err := c.MosaicSupply(id)
+	if err != nil {
+		return tx, err
+	}
+	address, err := crypto.AddressFromPublicKeyHex(creator.String(), c.network)
+	if err != nil {
+		return tx, err
+	}
+	owned, err := c.ownedQuantity(address, id)
+	if err != nil {
+		return tx, err
+	}
+	unit := int64(1)
+	for i := 0; i < div; i++ {
+		unit *= 10
+	}
+	if def.Property("supplyMutable") != "true" && owned != supply*unit {
+		return tx, errors.Errorf("supply of %s is immutable and the creator does not own all of it", id)
+	}
+	if supplyType == MosaicSupplyIncrease {
+		if delta > MaxMosaicSupply {
+			return tx, errors.Errorf("supply change of %d is too large", delta)
+		}
+		if err := validateSupply(supply + delta); err != nil {
+			return tx, err
+		}
+	} else if delta > owned/unit {
+		return tx, errors.Errorf("creator owns %d of %s, cannot decrease the supply by %d", owned/unit, id, delta)
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = MosaicSupplyChangeTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      MosaicSupplyChangeType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(creator.String()),
+			Deadline:  now.Add(o.deadline)},
+		MosaicID:   id,
+		SupplyType: supplyType,
+		Delta:      delta}
+	return tx, c.setFee(&tx, o)
+}
+
+// ownedQuantity returns the quantity of a mosaic an address owns, in
+// its smallest unit
+func (c Client) ownedQuantity(address string, id MosaicID) (int64, error) {
+	owned, err := c.MosaicsOwned(address)
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range owned {
+		if m.MosaicID == id {
+			return int64(m.Quantity), nil
+		}
+	}
+	return 0, nil
+}
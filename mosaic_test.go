@@ -0,0 +1,247 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+func TestParseMosaicID(t *testing.T) {
+	got, err := ParseMosaicID("alice.drinks:orange juice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MosaicID{NamespaceID: "alice.drinks", Name: "orange juice"}
+	if got != want {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
+	}
+	for _, s := range []string{"", "xem", ":xem", "nem:"} {
+		if _, err := ParseMosaicID(s); err == nil {
+			t.Fatalf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestMosaicDefinitions(t *testing.T) {
+	got, err := clientMock.MosaicDefinitions("alice.drinks", 0, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 definitions, got %d", len(got))
+	}
+	if got[0].Meta.ID != 161 || got[0].Mosaic.Levy != nil {
+		t.Fatalf("unexpected definition %+v", got[0])
+	}
+	want := &MosaicLevy{
+		Type:      MosaicLevyAbsolute,
+		Recipient: "TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S",
+		MosaicID:  XEM,
+		Fee:       10}
+	if !reflect.DeepEqual(want, got[1].Mosaic.Levy) {
+		t.Fatalf("\nWanted: %+v\n   Got: %+v", want, got[1].Mosaic.Levy)
+	}
+}
+
+func TestMosaicDefinition(t *testing.T) {
+	def, err := clientMock.MosaicDefinition(MosaicID{NamespaceID: "alice.drinks", Name: "orange juice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Description != "the best juice" || def.Property("initialSupply") != "1000000" {
+		t.Fatalf("unexpected definition %+v", def)
+	}
+	if div, err := def.Divisibility(); err != nil || div != 3 {
+		t.Fatalf("\nWanted: %v\n   Got: %v (%v)", 3, div, err)
+	}
+	if _, err := clientMock.MosaicDefinition(MosaicID{NamespaceID: "alice.drinks", Name: "water"}); err == nil {
+		t.Fatal("expected an error for an unknown mosaic")
+	}
+}
+
+func TestMosaicSupply(t *testing.T) {
+	got, err := clientMock.MosaicSupply(MosaicID{NamespaceID: "alice.drinks", Name: "orange juice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1000000 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 1000000, got)
+	}
+}
+
+func TestMosaicSink(t *testing.T) {
+	for _, network := range []byte{Mainnet, Testnet} {
+		sink, err := MosaicSink(network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := crypto.ValidateAddress(sink, network); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidateMosaicDefinition(t *testing.T) {
+	valid := func() MosaicDefinition {
+		return MosaicDefinition{
+			Creator:     testSigner,
+			ID:          MosaicID{NamespaceID: "alice.drinks", Name: "points"},
+			Description: "loyalty points",
+			Properties:  MosaicProperties{Divisibility: 2, InitialSupply: 1000000, SupplyMutable: true}.Properties(),
+			Levy: &MosaicLevy{
+				Type:      MosaicLevyPercentile,
+				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+				MosaicID:  XEM,
+				Fee:       10}}
+	}
+	if err := ValidateMosaicDefinition(valid(), Testnet); err != nil {
+		t.Fatal(err)
+	}
+	spaced := valid()
+	spaced.ID.Name = "orange juice"
+	if err := ValidateMosaicDefinition(spaced, Testnet); err != nil {
+		t.Fatal(err)
+	}
+	for name, change := range map[string]func(*MosaicDefinition){
+		"name":           func(d *MosaicDefinition) { d.ID.Name = "Points" },
+		"long name":      func(d *MosaicDefinition) { d.ID.Name = strings.Repeat("p", 33) },
+		"leading space":  func(d *MosaicDefinition) { d.ID.Name = " points" },
+		"namespace":      func(d *MosaicDefinition) { d.ID.NamespaceID = "nem" },
+		"creator":        func(d *MosaicDefinition) { d.Creator = "abcd" },
+		"description":    func(d *MosaicDefinition) { d.Description = strings.Repeat("x", 513) },
+		"divisibility":   func(d *MosaicDefinition) { d.Properties[0].Value = "7" },
+		"supply":         func(d *MosaicDefinition) { d.Properties[1].Value = "9000000001" },
+		"flag":           func(d *MosaicDefinition) { d.Properties[2].Value = "maybe" },
+		"levy type":      func(d *MosaicDefinition) { d.Levy.Type = 3 },
+		"levy recipient": func(d *MosaicDefinition) { d.Levy.Recipient = "NALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS" },
+		"levy fee":       func(d *MosaicDefinition) { d.Levy.Fee = 0 },
+	} {
+		d := valid()
+		change(&d)
+		if err := ValidateMosaicDefinition(d, Testnet); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+// mosaicClient is a client where testSigner owns alice.drinks and created
+// alice.drinks:points, with 2 decimals, a supply of 1000 and owning owned
+// of it in its smallest unit
+func mosaicClient(t *testing.T, mutable bool, owned int64) Client {
+	owner, err := crypto.AddressFromPublicKeyHex(testSigner, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := namespaceClient(100000, map[string]NamespaceInfo{
+		"alice":        {FQN: "alice", Owner: owner, Height: 90000},
+		"alice.drinks": {FQN: "alice.drinks", Owner: owner, Height: 95000}})
+	namespaces := c.request
+	c.request = func(req *http.Request) ([]byte, error) {
+		switch req.URL.Path {
+		case "/namespace/mosaic/definition/page":
+			return json.Marshal(map[string]interface{}{
+				"data": []interface{}{map[string]interface{}{
+					"meta": map[string]int{"id": 1},
+					"mosaic": MosaicDefinition{
+						Creator:    testSigner,
+						ID:         MosaicID{NamespaceID: "alice.drinks", Name: "points"},
+						Properties: MosaicProperties{Divisibility: 2, InitialSupply: 1000, SupplyMutable: mutable}.Properties()}}}})
+		case "/mosaic/supply":
+			return []byte(`{"supply": 1000}`), nil
+		case "/account/mosaic/owned":
+			return []byte(fmt.Sprintf(`{"data": [{"mosaicId": {"namespaceId": "alice.drinks", "name": "points"}, "quantity": %d}]}`, owned)), nil
+		}
+		return namespaces(req)
+	}
+	return c
+}
+
+func TestNewMosaicDefinition(t *testing.T) {
+	c := mosaicClient(t, true, 100000)
+	levy := &MosaicLevy{Type: MosaicLevyAbsolute, Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", MosaicID: XEM, Fee: 5}
+	props := MosaicProperties{Divisibility: 3, InitialSupply: 500000, SupplyMutable: true, Transferable: true}
+	id := MosaicID{NamespaceID: "alice.drinks", Name: "stamps"}
+	tx, err := c.NewMosaicDefinition(testSigner, id, "coffee stamps", props, levy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.CreationFee != 10000000 || tx.CreationFeeSink != testnetMosaicSink || tx.Fee != 150000 || tx.MosaicDefinition.Property("initialSupply") != "500000" {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	data, err := Serialize(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Deserialize(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, &tx) {
+		t.Fatalf("\nWanted: %+v\n   Got: %+v", &tx, again)
+	}
+	if _, err := c.NewMosaicDefinition(testSigner, MosaicID{NamespaceID: "bob", Name: "stamps"}, "", props, nil); err == nil {
+		t.Fatal("expected an error for a namespace not owned by the creator")
+	}
+	props.Divisibility = 7
+	if _, err := c.NewMosaicDefinition(testSigner, id, "", props, nil); err == nil {
+		t.Fatal("expected an error for an invalid divisibility")
+	}
+}
+
+func TestNewMosaicSupplyChange(t *testing.T) {
+	id := MosaicID{NamespaceID: "alice.drinks", Name: "points"}
+	c := mosaicClient(t, true, 60000)
+	tx, err := c.NewMosaicSupplyChange(testSigner, id, MosaicSupplyIncrease, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Delta != 5000 || tx.SupplyType != MosaicSupplyIncrease || tx.Fee != 150000 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	// the creator owns 600 of the 1000 points
+	if _, err := c.NewMosaicSupplyChange(testSigner, id, MosaicSupplyDecrease, 600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name       string
+		c          Client
+		supplyType int
+		delta      int64
+	}{
+		{"decrease above owned", c, MosaicSupplyDecrease, 601},
+		{"increase above maximum", c, MosaicSupplyIncrease, MaxMosaicSupply},
+		{"zero", c, MosaicSupplyIncrease, 0},
+		{"type", c, 3, 1},
+		{"immutable", mosaicClient(t, false, 60000), MosaicSupplyIncrease, 1},
+	} {
+		if _, err := tc.c.NewMosaicSupplyChange(testSigner, id, tc.supplyType, tc.delta); err == nil {
+			t.Fatalf("%s: expected an error", tc.name)
+		}
+	}
+	// an immutable supply can change while the creator owns all of it
+	if _, err := mosaicClient(t, false, 100000).NewMosaicSupplyChange(testSigner, id, MosaicSupplyDecrease, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NewMosaicSupplyChange(treasuryKey, id, MosaicSupplyIncrease, 1); err == nil {
+		t.Fatal("expected an error for an account that is not the creator")
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+// MaxCosignatories is the largest number of cosignatories a multisig
+// account can have
+const MaxCosignatories = 32
+
+// AddCosignatory is a modification adding pk as a cosignatory
+func AddCosignatory(pk PublicKey) CosignatoryModification {
+	return CosignatoryModification{ModificationType: CosignatoryAdd, CosignatoryAccount: strings.ToLower(pk.String())}
+}
+
+// DeleteCosignatory is a modification removing pk as a cosignatory
+func DeleteCosignatory(pk PublicKey) CosignatoryModification {
+	return CosignatoryModification{ModificationType: CosignatoryDelete, CosignatoryAccount: strings.ToLower(pk.String())}
+}
+
+// NewMultisigModification will build an unsigned multisig aggregate
+// modification of account. It converts a normal account into a multisig
+// account, or changes the cosignatories of a multisig account, and
+// changes the minimum number of cosignatories by minChange. The
+// modifications are sorted the way NIS sorts them before it verifies
+// the signature.
+//
+// The modification is checked against the current metadata of account
+// with ValidateMultisigModification. A conversion is signed by account
+// itself, changes to a multisig account have to be wrapped in a multisig
+// transaction signed by one of its cosignatories.
+func (c Client) NewMultisigModification(account PublicKey, mods []CosignatoryModification, minChange int, opts ...TxOption) (MultisigAggregateModificationTransaction, error) {
+	var tx MultisigAggregateModificationTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	meta, err := c.AccountData(account)
+	if err != nil {
+		return tx, err
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = MultisigAggregateModificationTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      MultisigAggregateModificationType,
+			Version:   version(c.network, 2),
+			TimeStamp: now,
+			Signer:    strings.ToLower(account.String()),
+			Deadline:  now.Add(o.deadline)},
+		Modifications:    mods,
+		MinCosignatories: minChange}
+	if err := ValidateMultisigModification(meta, &tx); err != nil {
+		return tx, err
+	}
+	if tx.Modifications, err = sortModifications(mods, c.network); err != nil {
+		return tx, err
+	}
+	return tx, c.setFee(&tx, o)
+}
+
+// sortModifications returns the modifications in the order NIS keeps
+// them, by modification type and then by cosignatory address
+func sortModifications(mods []CosignatoryModification, network byte) ([]CosignatoryModification, error) {
+	addresses := make(map[string]string, len(mods))
+	for _, m := range mods {
+		a, err := crypto.AddressFromPublicKeyHex(m.CosignatoryAccount, network)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cosignatory %q", m.CosignatoryAccount)
+		}
+		addresses[m.CosignatoryAccount] = a
+	}
+	sorted := append([]CosignatoryModification(nil), mods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ModificationType != sorted[j].ModificationType {
+			return sorted[i].ModificationType < sorted[j].ModificationType
+		}
+		return addresses[sorted[i].CosignatoryAccount] < addresses[sorted[j].CosignatoryAccount]
+	})
+	return sorted, nil
+}
+
+// ValidateMultisigModification checks locally that a modification can
+// be applied to an account with the given metadata, following the rules
+// NIS enforces:
+// every cosignatory is added or removed at most once, only existing
+// cosignatories are removed and only one of them at a time, a normal
+// account cannot remove cosignatories, the account cannot be its own
+// cosignatory nor be converted while it is a cosignatory of another
+// account, and the result has at most MaxCosignatories cosignatories
+// and a minimum between 0 and their count.
+func ValidateMultisigModification(account AccountMetadataPair, tx *MultisigAggregateModificationTransaction) error {
+	if len(tx.Modifications) == 0 && tx.MinCosignatories == 0 {
+		return errors.New("modification changes nothing")
+	}
+	current := make(map[string]bool)
+	for _, cos := range account.Meta.Cosignatories {
+		current[strings.ToLower(cos.PublicKey)] = true
+	}
+	self := strings.ToLower(account.Account.PublicKey)
+	if self == "" {
+		self = strings.ToLower(tx.Signer)
+	}
+	if len(current) == 0 && len(account.Meta.CosignatoryOf) > 0 {
+		return errors.New("a cosignatory of a multisig account cannot be converted to multisig")
+	}
+	seen := make(map[string]bool)
+	deletes := 0
+	count := len(current)
+	for _, m := range tx.Modifications {
+		pk := strings.ToLower(m.CosignatoryAccount)
+		if err := crypto.ValidatePublicKey(pk); err != nil {
+			return errors.Wrapf(err, "invalid cosignatory %q", m.CosignatoryAccount)
+		}
+		if seen[pk] {
+			return errors.Errorf("cosignatory %s is modified more than once", pk)
+		}
+		seen[pk] = true
+		switch m.ModificationType {
+		case CosignatoryAdd:
+			if pk == self {
+				return errors.New("an account cannot be its own cosignatory")
+			}
+			if current[pk] {
+				return errors.Errorf("%s is already a cosignatory", pk)
+			}
+			count++
+		case CosignatoryDelete:
+			if !current[pk] {
+				return errors.Errorf("%s is not a cosignatory", pk)
+			}
+			if deletes++; deletes > 1 {
+				return errors.New("only one cosignatory can be removed at a time")
+			}
+			count--
+		default:
+			return errors.Errorf("unknown modification type %d", m.ModificationType)
+		}
+	}
+	if count > MaxCosignatories {
+		return errors.Errorf("%d cosignatories is more than the maximum of %d", count, MaxCosignatories)
+	}
+	min := account.Account.MultisigInfo.MinCosignatories + tx.MinCosignatories
+	if min < 0 {
+		return errors.Errorf("minimum cosignatories would be %d", min)
+	}
+	if min > count {
+		return errors.Errorf("minimum cosignatories %d is more than the %d cosignatories", min, count)
+	}
+	return nil
+}
+
+// NewMultisigTransaction will build an unsigned multisig transaction
+// wrapping inner, a transaction signed by a multisig account. The
+// cosigner initiating it pays the fee of the wrapper and the multisig
+// account the fee of inner. The other cosignatories then cosign the
+// hash of inner with NewMultisigSignature.
+func (c Client) NewMultisigTransaction(cosigner PublicKey, inner TransactionEntity, opts ...TxOption) (MultisigTransaction, error) {
+	var tx MultisigTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	if inner == nil {
+		return tx, errors.New("no inner transaction")
+	}
+	if _, ok := inner.(*MultisigTransaction); ok {
+		return tx, errors.New("multisig transactions cannot be nested")
+	}
+	ic := inner.Common()
+	if ic.Network() != c.network {
+		return tx, errors.Errorf("inner transaction is for network 0x%x", ic.Network())
+	}
+	meta, err := c.AccountData(cosigner)
+	if err != nil {
+		return tx, err
+	}
+	if !isCosignatoryOf(meta, ic.Signer) {
+		return tx, errors.Errorf("%s is not a cosignatory of %s", cosigner, ic.Signer)
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = MultisigTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      MultisigType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(cosigner.String()),
+			Deadline:  now.Add(o.deadline)},
+		OtherTrans: inner}
+	return tx, c.setFee(&tx, o)
+}
+
+// InnerHash returns the hash of the inner transaction, which the
+// cosignatories sign with a multisig signature
+func (t *MultisigTransaction) InnerHash() (string, error) {
+	if t.OtherTrans == nil {
+		return "", errors.New("multisig transaction has no inner transaction")
+	}
+	data, err := Serialize(t.OtherTrans)
+	if err != nil {
+		return "", err
+	}
+	return TransactionHash(data), nil
+}
+
+// NewMultisigSignature will build an unsigned multisig signature of the
+// pending multisig transaction of multisigAccount, an address, whose
+// inner transaction has the hash innerHash
+func (c Client) NewMultisigSignature(cosigner PublicKey, innerHash string, multisigAccount string, opts ...TxOption) (MultisigSignatureTransaction, error) {
+	var tx MultisigSignatureTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	if err := cosigner.validate(c.network); err != nil {
+		return tx, errors.Wrap(err, "invalid cosigner")
+	}
+	if err := Address(multisigAccount).validate(c.network); err != nil {
+		return tx, errors.Wrapf(err, "invalid multisig account %q", multisigAccount)
+	}
+	if b, err := hex.DecodeString(innerHash); err != nil || len(b) != 32 {
+		return tx, errors.Errorf("invalid inner hash %q", innerHash)
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = MultisigSignatureTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      MultisigSignatureType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(cosigner.String()),
+			Deadline:  now.Add(o.deadline)},
+		OtherHash:    strings.ToLower(innerHash),
+		OtherAccount: multisigAccount}
+	return tx, c.setFee(&tx, o)
+}
+
+// UnconfirmedTransactionMetadataPair is an unconfirmed transaction and
+// its metadata
+type UnconfirmedTransactionMetadataPair struct {
+	Meta struct {
+		// Data is the hash of the inner transaction of a multisig
+		// transaction
+		Data string
+	}
+	Transaction Transaction
+}
+
+// UnconfirmedTransactions will list the unconfirmed transactions of an
+// address, including the pending multisig transactions of the accounts
+// it is a cosignatory of
+func (c Client) UnconfirmedTransactions(address string) ([]UnconfirmedTransactionMetadataPair, error) {
+	var data struct {
+		Data []UnconfirmedTransactionMetadataPair
+	}
+	c.url.Path = "/account/unconfirmedTransactions"
+	req, err := c.buildReq(map[string]string{"address": address}, nil, http.MethodGet)
+	if err != nil {
+		return data.Data, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return data.Data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data.Data, err
+	}
+	return data.Data, nil
+}
+
+// PendingMultisig is a multisig transaction waiting for cosignatures
+type PendingMultisig struct {
+	// InnerHash is the hash cosignatories sign
+	InnerHash string
+	// MultisigAccount is the address of the multisig account
+	MultisigAccount string
+	// Transaction is the multisig transaction, its OtherTrans is the
+	// inner transaction
+	Transaction Transaction
+	// Signers are the public keys of the cosignatories that signed so
+	// far, starting with the one initiating the transaction
+	Signers []string
+}
+
+// Signatures returns the number of signatures the transaction has
+func (p PendingMultisig) Signatures() int {
+	return len(p.Signers)
+}
+
+// PendingMultisig will list the pending multisig transactions waiting
+// for the signature of cosigner, grouped by the hash of their inner
+// transaction. Use NewMultisigSignature with the InnerHash and
+// MultisigAccount of a result to cosign it.
+func (c Client) PendingMultisig(cosigner PublicKey) ([]PendingMultisig, error) {
+	address, err := crypto.AddressFromPublicKeyHex(cosigner.String(), c.network)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cosigner")
+	}
+	txs, err := c.UnconfirmedTransactions(address)
+	if err != nil {
+		return nil, err
+	}
+	var pending []PendingMultisig
+	byHash := make(map[string]int)
+	for _, tx := range txs {
+		t := tx.Transaction
+		if t.Type != MultisigType || t.OtherTrans == nil || tx.Meta.Data == "" {
+			continue
+		}
+		i, ok := byHash[tx.Meta.Data]
+		if !ok {
+			account, err := crypto.AddressFromPublicKeyHex(t.OtherTrans.Signer, c.network)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid multisig account of %s", tx.Meta.Data)
+			}
+			i = len(pending)
+			byHash[tx.Meta.Data] = i
+			pending = append(pending, PendingMultisig{
+				InnerHash:       tx.Meta.Data,
+				MultisigAccount: account,
+				Transaction:     t})
+		}
+		p := &pending[i]
+		p.addSigner(t.Signer)
+		for _, sig := range t.Signatures {
+			p.addSigner(sig.Signer)
+		}
+	}
+	// leave out what cosigner already signed
+	waiting := pending[:0]
+	for _, p := range pending {
+		if !p.signedBy(cosigner.String()) {
+			waiting = append(waiting, p)
+		}
+	}
+	return waiting, nil
+}
+
+func (p *PendingMultisig) addSigner(pk string) {
+	if !p.signedBy(pk) {
+		p.Signers = append(p.Signers, strings.ToLower(pk))
+	}
+}
+
+func (p PendingMultisig) signedBy(pk string) bool {
+	for _, s := range p.Signers {
+		if strings.EqualFold(s, pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCosignatoryOf reports whether the account with the given metadata is
+// a cosignatory of the account with public key multisig
+func isCosignatoryOf(account AccountMetadataPair, multisig string) bool {
+	for _, m := range account.Meta.CosignatoryOf {
+		if strings.EqualFold(m.PublicKey, multisig) {
+			return true
+		}
+	}
+	return false
+}
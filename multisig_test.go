@@ -0,0 +1,262 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+const (
+	treasuryKey = "a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1a"
+	cosigner1   = "b4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6"
+	cosigner2   = "c4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6"
+	cosigner3   = "d4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6"
+	cosigner4   = "e4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6"
+)
+
+// treasury is a 2 of 3 multisig account
+func treasury() AccountMetadataPair {
+	var a AccountMetadataPair
+	a.Account.PublicKey = treasuryKey
+	a.Account.MultisigInfo = MultisigInfo{CosignatoriesCount: 3, MinCosignatories: 2}
+	for _, pk := range []string{cosigner1, cosigner2, cosigner3} {
+		a.Meta.Cosignatories = append(a.Meta.Cosignatories, AccountInfo{PublicKey: pk})
+	}
+	return a
+}
+
+func TestNewMultisigModification(t *testing.T) {
+	mods := []CosignatoryModification{
+		AddCosignatory(cosigner1),
+		AddCosignatory(cosigner2),
+		AddCosignatory(cosigner3)}
+	tx, err := clientMock.NewMultisigModification(treasuryKey, mods, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxVersion() != 2 || tx.Signer != treasuryKey || tx.Fee != 500000 || tx.Deadline-tx.TimeStamp != 3600 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if _, err := Serialize(&tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientMock.NewMultisigModification(treasuryKey, mods, 4); err == nil {
+		t.Fatal("expected an error for a minimum above the cosignatory count")
+	}
+}
+
+func TestNewMultisigModificationSorted(t *testing.T) {
+	c := Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			if req.URL.Path == "/account/get/from-public-key" {
+				return []byte(`{
+  "account": {"publicKey": "` + treasuryKey + `"},
+  "meta": {"cosignatories": [{"publicKey": "` + cosigner1 + `"}, {"publicKey": "` + cosigner2 + `"}]}
+}`), nil
+			}
+			return sendReqMock(req)
+		}}
+	mods := []CosignatoryModification{
+		DeleteCosignatory(cosigner1),
+		AddCosignatory(cosigner4),
+		AddCosignatory(cosigner3)}
+	tx, err := c.NewMultisigModification(treasuryKey, mods, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// additions come before deletions, each sorted by address
+	want := []CosignatoryModification{AddCosignatory(cosigner3), AddCosignatory(cosigner4), DeleteCosignatory(cosigner1)}
+	a3, err := crypto.AddressFromPublicKeyHex(cosigner3, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a4, err := crypto.AddressFromPublicKeyHex(cosigner4, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a4 < a3 {
+		want[0], want[1] = want[1], want[0]
+	}
+	if !reflect.DeepEqual(want, tx.Modifications) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, tx.Modifications)
+	}
+	if mods[0] != DeleteCosignatory(cosigner1) {
+		t.Fatal("expected the modifications passed in to be left as they were")
+	}
+}
+
+func TestNewMultisigModificationMetadata(t *testing.T) {
+	c := Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			if req.URL.Path == "/account/get/from-public-key" {
+				return []byte(`{
+  "account": {
+     "publicKey": "` + treasuryKey + `",
+     "multisigInfo": {"cosignatoriesCount": 3, "minCosignatories": 2}
+  },
+  "meta": {
+     "cosignatories": [
+        {"publicKey": "` + cosigner1 + `"},
+        {"publicKey": "` + cosigner2 + `"},
+        {"publicKey": "` + cosigner3 + `"}
+     ]
+  }
+}`), nil
+			}
+			return sendReqMock(req)
+		}}
+	// removing a cosignatory of a 2 of 3 account requires lowering the
+	// minimum to keep a 2 of 2 account
+	if _, err := c.NewMultisigModification(treasuryKey, []CosignatoryModification{DeleteCosignatory(cosigner3)}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NewMultisigModification(treasuryKey, []CosignatoryModification{DeleteCosignatory(cosigner3)}, 1); err == nil {
+		t.Fatal("expected an error for a minimum above the cosignatory count")
+	}
+}
+
+func TestValidateMultisigModification(t *testing.T) {
+	normal := AccountMetadataPair{}
+	normal.Account.PublicKey = treasuryKey
+	cosignatory := normal
+	cosignatory.Meta.CosignatoryOf = []AccountInfo{{PublicKey: cosigner4}}
+	for _, tc := range []struct {
+		name    string
+		account AccountMetadataPair
+		mods    []CosignatoryModification
+		min     int
+		valid   bool
+	}{
+		{"convert", normal, []CosignatoryModification{AddCosignatory(cosigner1), AddCosignatory(cosigner2)}, 1, true},
+		{"add and raise minimum", treasury(), []CosignatoryModification{AddCosignatory(cosigner4)}, 1, true},
+		{"only raise minimum", treasury(), nil, 1, true},
+		{"remove one", treasury(), []CosignatoryModification{DeleteCosignatory(cosigner1)}, 0, true},
+		{"nothing", treasury(), nil, 0, false},
+		{"minimum above count", treasury(), nil, 2, false},
+		{"negative minimum", treasury(), nil, -3, false},
+		{"add existing", treasury(), []CosignatoryModification{AddCosignatory(cosigner1)}, 0, false},
+		{"add self", treasury(), []CosignatoryModification{AddCosignatory(treasuryKey)}, 0, false},
+		{"add twice", normal, []CosignatoryModification{AddCosignatory(cosigner1), AddCosignatory(cosigner1)}, 0, false},
+		{"remove unknown", treasury(), []CosignatoryModification{DeleteCosignatory(cosigner4)}, 0, false},
+		{"remove two", treasury(), []CosignatoryModification{DeleteCosignatory(cosigner1), DeleteCosignatory(cosigner2)}, -1, false},
+		{"remove from normal", normal, []CosignatoryModification{DeleteCosignatory(cosigner1)}, 0, false},
+		{"convert cosignatory", cosignatory, []CosignatoryModification{AddCosignatory(cosigner1)}, 0, false},
+		{"invalid key", normal, []CosignatoryModification{AddCosignatory("abcd")}, 0, false},
+	} {
+		tx := &MultisigAggregateModificationTransaction{Modifications: tc.mods, MinCosignatories: tc.min}
+		err := ValidateMultisigModification(tc.account, tx)
+		if tc.valid && err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Fatalf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestNewMultisigTransaction(t *testing.T) {
+	c := Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			if req.URL.Path == "/account/get/from-public-key" {
+				return []byte(`{
+  "account": {"publicKey": "` + testSigner + `"},
+  "meta": {"cosignatoryOf": [{"publicKey": "` + treasuryKey + `"}]}
+}`), nil
+			}
+			return sendReqMock(req)
+		}}
+	inner, err := c.NewTransfer(treasuryKey, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := c.NewMultisigTransaction(testSigner, &inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Signer != testSigner || tx.Fee != 150000 || tx.OtherTrans != TransactionEntity(&inner) {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	data, err := Serialize(&inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, err := tx.InnerHash(); err != nil || h != TransactionHash(data) {
+		t.Fatalf("\nWanted: %v\n   Got: %v (%v)", TransactionHash(data), h, err)
+	}
+	if _, err := c.NewMultisigTransaction(testSigner, &tx); err == nil {
+		t.Fatal("expected an error for a nested multisig transaction")
+	}
+	// the mock account is not a cosignatory of anything
+	if _, err := clientMock.NewMultisigTransaction(testSigner, &inner); err == nil {
+		t.Fatal("expected an error for an account that is not a cosignatory")
+	}
+}
+
+func TestNewMultisigSignature(t *testing.T) {
+	hash := "15c373ad4c3fe6af47d1941379ff262f785bdcfa07c02ac3608bc10da27d5e82"
+	tx, err := clientMock.NewMultisigSignature(testSigner, hash, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.OtherHash != hash || tx.Fee != 150000 || tx.Type != MultisigSignatureType {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if _, err := Serialize(&tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientMock.NewMultisigSignature(testSigner, "abcd", "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS"); err == nil {
+		t.Fatal("expected an error for an invalid hash")
+	}
+	if _, err := clientMock.NewMultisigSignature(testSigner, hash, "TALICE"); err == nil {
+		t.Fatal("expected an error for an invalid multisig account")
+	}
+}
+
+func TestPendingMultisig(t *testing.T) {
+	got, err := clientMock.PendingMultisig(testSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 pending transactions, got %d", len(got))
+	}
+	want := []struct {
+		hash    string
+		signers []string
+	}{
+		{strings.Repeat("aa", 32), []string{cosigner1, cosigner2, cosigner3}},
+		{strings.Repeat("cc", 32), []string{cosigner2}},
+	}
+	for i, w := range want {
+		if got[i].InnerHash != w.hash || !reflect.DeepEqual(got[i].Signers, w.signers) || got[i].Signatures() != len(w.signers) {
+			t.Fatalf("\nWanted: %v %v\n   Got: %v %v", w.hash, w.signers, got[i].InnerHash, got[i].Signers)
+		}
+	}
+	account, err := crypto.AddressFromPublicKeyHex(treasuryKey, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].MultisigAccount != account || got[0].Transaction.OtherTrans.Amount != 1000000 {
+		t.Fatalf("unexpected pending transaction %+v", got[0])
+	}
+}
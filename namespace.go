@@ -17,7 +17,12 @@ package nemgo
 import (
 	"encoding/json"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
 )
 
 // NamespaceMetadataPair contains info and metadata about a namespace
@@ -51,7 +56,7 @@ func (c Client) RootNamespace(ID int, PageSize int) ([]NamespaceMetadataPair, er
 		return data.Data, err
 	}
 	if err = json.Unmarshal(body, &data); err != nil {
-		return data.Data, nil
+		return data.Data, err
 	}
 	return data.Data, nil
 }
@@ -73,3 +78,184 @@ func (c Client) Namespace(namespace string) (NamespaceInfo, error) {
 	}
 	return data, nil
 }
+
+const (
+	// RootNamespaceRentalFee is the rental fee of a root namespace for a
+	// year, in micro XEM
+	RootNamespaceRentalFee = 100000000
+	// SubNamespaceRentalFee is the rental fee of a sub-namespace, in
+	// micro XEM
+	SubNamespaceRentalFee = 10000000
+	// NamespaceDuration is the number of blocks a root namespace is
+	// rented for, a year of one minute blocks
+	NamespaceDuration = 525600
+	// NamespaceRenewalWindow is the number of blocks before the expiry of
+	// a root namespace during which its owner can renew it, a month
+	NamespaceRenewalWindow = 43200
+
+	maxNamespaceLevels   = 3
+	maxRootNamespaceLen  = 16
+	maxNamespacePartLen  = 64
+	mainnetNamespaceSink = "NAMESPACEWH4MKFMBCVFERDPOOP4FK7MTBXDPZZA"
+	testnetNamespaceSink = "TAMESPACEWH4MKFMBCVFERDPOOP4FK7MTDJEYP35"
+)
+
+var namespacePart = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// reservedRootNamespaces cannot be provisioned by anyone
+var reservedRootNamespaces = map[string]bool{
+	"nem": true, "user": true, "account": true, "org": true, "com": true,
+	"biz": true, "net": true, "edu": true, "mil": true, "gov": true, "info": true,
+}
+
+// NamespaceSink returns the address rental fees are paid to on a network
+func NamespaceSink(network byte) (string, error) {
+	switch network {
+	case Mainnet:
+		return mainnetNamespaceSink, nil
+	case Testnet:
+		return testnetNamespaceSink, nil
+	}
+	return "", errors.Errorf("unknown network 0x%x", network)
+}
+
+// ValidateNamespace checks the name rules of a fully qualified namespace
+// such as "alice.drinks.juice": at most three parts of lowercase letters,
+// digits, '-' and '_' starting with a letter or digit, a root of at most
+// 16 characters that is not reserved and other parts of at most 64.
+func ValidateNamespace(name string) error {
+	parts := strings.Split(name, ".")
+	if len(parts) > maxNamespaceLevels {
+		return errors.Errorf("namespace %q has more than %d levels", name, maxNamespaceLevels)
+	}
+	for i, p := range parts {
+		if !namespacePart.MatchString(p) {
+			return errors.Errorf("invalid namespace part %q in %q", p, name)
+		}
+		if i == 0 && len(p) > maxRootNamespaceLen {
+			return errors.Errorf("root namespace %q is longer than %d characters", p, maxRootNamespaceLen)
+		}
+		if len(p) > maxNamespacePartLen {
+			return errors.Errorf("namespace part %q is longer than %d characters", p, maxNamespacePartLen)
+		}
+	}
+	if reservedRootNamespaces[parts[0]] {
+		return errors.Errorf("root namespace %q is reserved", parts[0])
+	}
+	return nil
+}
+
+// ExpiryHeight returns the height at which a root namespace expires
+func (n NamespaceInfo) ExpiryHeight() int {
+	return n.Height + NamespaceDuration
+}
+
+// NewProvisionNamespace will build an unsigned transaction provisioning
+// the namespace name, such as "alice" or "alice.drinks", for signer.
+//
+// A root namespace can be provisioned when it is free or, by its owner,
+// to renew it during the last month before it expires. A sub-namespace
+// needs its parent to be owned by signer. The builder warns, see
+// WithWarning, when the root namespace will expire within a month and
+// should be renewed.
+func (c Client) NewProvisionNamespace(signer PublicKey, name string, opts ...TxOption) (ProvisionNamespaceTransaction, error) {
+	var tx ProvisionNamespaceTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	if err := ValidateNamespace(name); err != nil {
+		return tx, err
+	}
+	owner, err := crypto.AddressFromPublicKeyHex(signer.String(), c.network)
+	if err != nil {
+		return tx, errors.Wrap(err, "invalid signer")
+	}
+	sink, err := NamespaceSink(c.network)
+	if err != nil {
+		return tx, err
+	}
+	height, err := c.Height()
+	if err != nil {
+		return tx, err
+	}
+	parts := strings.Split(name, ".")
+	root, err := c.Namespace(parts[0])
+	found := err == nil
+	if err != nil && !IsNotFound(err) {
+		return tx, err
+	}
+	registered := found && height < root.ExpiryHeight()
+	left := root.ExpiryHeight() - height
+	fee := int64(RootNamespaceRentalFee)
+	if len(parts) == 1 {
+		switch {
+		// the owner keeps the namespace for a month after it expires
+		case found && root.Owner != owner && left > -NamespaceRenewalWindow:
+			return tx, errors.Errorf("namespace %q is owned by %s", name, root.Owner)
+		case registered && left > NamespaceRenewalWindow:
+			return tx, errors.Errorf("namespace %q can only be renewed in the last %d blocks before it expires, it expires in %d", name, NamespaceRenewalWindow, left)
+		}
+	} else {
+		if !registered || root.Owner != owner {
+			return tx, errors.Errorf("root namespace %q is not owned by %s", parts[0], owner)
+		}
+		if len(parts) == 3 {
+			if _, err := c.Namespace(strings.Join(parts[:2], ".")); IsNotFound(err) {
+				return tx, errors.Errorf("namespace %q does not exist", strings.Join(parts[:2], "."))
+			} else if err != nil {
+				return tx, err
+			}
+		}
+		if left <= NamespaceRenewalWindow {
+			o.warn(errors.Errorf("root namespace %q expires in %d blocks, at height %d, and needs to be renewed", parts[0], left, root.ExpiryHeight()))
+		}
+		fee = SubNamespaceRentalFee
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = ProvisionNamespaceTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      ProvisionNamespaceType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(signer.String()),
+			Deadline:  now.Add(o.deadline)},
+		RentalFeeSink: sink,
+		RentalFee:     fee,
+		NewPart:       parts[len(parts)-1],
+		Parent:        strings.Join(parts[:len(parts)-1], ".")}
+	return tx, c.setFee(&tx, o)
+}
+
+// checkNamespaceOwner checks that a namespace exists, has not expired
+// and is owned by owner
+func (c Client) checkNamespaceOwner(owner PublicKey, namespace string) error {
+	address, err := crypto.AddressFromPublicKeyHex(owner.String(), c.network)
+	if err != nil {
+		return errors.Wrap(err, "invalid owner")
+	}
+	root, err := c.Namespace(strings.Split(namespace, ".")[0])
+	if IsNotFound(err) {
+		return errors.Errorf("namespace %q is not owned by %s", namespace, address)
+	} else if err != nil {
+		return err
+	}
+	height, err := c.Height()
+	if err != nil {
+		return err
+	}
+	if height >= root.ExpiryHeight() || root.Owner != address {
+		return errors.Errorf("namespace %q is not owned by %s", namespace, address)
+	}
+	if namespace != root.FQN {
+		if _, err := c.Namespace(namespace); IsNotFound(err) {
+			return errors.Errorf("namespace %q does not exist", namespace)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
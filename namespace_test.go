@@ -15,8 +15,17 @@
 package nemgo
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/myndshft/nemgo/crypto"
 )
 
 func TestRootNamespace(t *testing.T) {
@@ -69,3 +78,132 @@ func TestNamespace(t *testing.T) {
 // 		t.Fatalf("\nWanted: %v\n   Got: %v", want, got)
 // 	}
 // }
+
+// namespaceClient is a client at chain height with the given namespaces,
+// each registered at a height by an owner
+func namespaceClient(height int, namespaces map[string]NamespaceInfo) Client {
+	return Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			switch req.URL.Path {
+			case "/chain/height":
+				return []byte(fmt.Sprintf(`{"height": %d}`, height)), nil
+			case "/namespace":
+				ns, ok := namespaces[req.URL.Query().Get("namespace")]
+				if !ok {
+					return nil, &NISError{Status: 404, Reason: "Not Found", Message: "invalid name"}
+				}
+				return json.Marshal(ns)
+			}
+			return sendReqMock(req)
+		}}
+}
+
+func TestNamespaceSink(t *testing.T) {
+	for _, network := range []byte{Mainnet, Testnet} {
+		sink, err := NamespaceSink(network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := crypto.ValidateAddress(sink, network); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := NamespaceSink(0x60); err == nil {
+		t.Fatal("expected an error for an unknown network")
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for _, name := range []string{"alice", "alice.drinks", "alice.drinks.orange_juice", "1-2_3", strings.Repeat("a", 16) + "." + strings.Repeat("b", 64)} {
+		if err := ValidateNamespace(name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Alice", "a.b.c.d", "nem", "nem.foo", "-alice", "alice..drinks", "alice.drinks.", "al ice", strings.Repeat("a", 17), "a." + strings.Repeat("b", 65)} {
+		if err := ValidateNamespace(name); err == nil {
+			t.Fatalf("expected an error for %q", name)
+		}
+	}
+}
+
+func TestNewProvisionNamespace(t *testing.T) {
+	owner, err := crypto.AddressFromPublicKeyHex(testSigner, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := namespaceClient(100000, map[string]NamespaceInfo{
+		"alice":        {FQN: "alice", Owner: owner, Height: 90000},
+		"alice.drinks": {FQN: "alice.drinks", Owner: owner, Height: 95000},
+		"bob":          {FQN: "bob", Owner: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", Height: 90000}})
+	noWarning := WithWarning(func(err error) {
+		t.Fatalf("unexpected warning %v", err)
+	})
+	tx, err := c.NewProvisionNamespace(testSigner, "carol", noWarning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.NewPart != "carol" || tx.Parent != "" || tx.RentalFee != 100000000 || tx.RentalFeeSink != testnetNamespaceSink || tx.Fee != 150000 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	tx, err = c.NewProvisionNamespace(testSigner, "alice.drinks.juice", noWarning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.NewPart != "juice" || tx.Parent != "alice.drinks" || tx.RentalFee != 10000000 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if _, err := Serialize(&tx); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"alice", "bob", "bob.drinks", "alice.food.fruit", "Carol"} {
+		if _, err := c.NewProvisionNamespace(testSigner, name, noWarning); err == nil {
+			t.Fatalf("expected an error for %q", name)
+		}
+	}
+}
+
+func TestNewProvisionNamespaceExpiring(t *testing.T) {
+	owner, err := crypto.AddressFromPublicKeyHex(testSigner, Testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// alice expires in 1000 blocks, bob expired 1000 blocks ago
+	c := namespaceClient(600000, map[string]NamespaceInfo{
+		"alice": {FQN: "alice", Owner: owner, Height: 600000 + 1000 - NamespaceDuration},
+		"bob":   {FQN: "bob", Owner: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", Height: 600000 - 1000 - NamespaceDuration}})
+	var warnings []error
+	warn := WithWarning(func(err error) {
+		warnings = append(warnings, err)
+	})
+	if _, err := c.NewProvisionNamespace(testSigner, "alice.drinks", warn); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "expires in 1000 blocks") {
+		t.Fatalf("expected a warning about the expiry, got %v", warnings)
+	}
+	tx, err := c.NewProvisionNamespace(testSigner, "alice", warn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.RentalFee != 100000000 || tx.Parent != "" {
+		t.Fatalf("unexpected renewal %+v", tx)
+	}
+	// bob is kept for its owner for a month after it expires
+	if _, err := c.NewProvisionNamespace(testSigner, "bob", warn); err == nil {
+		t.Fatal("expected an error for a namespace in its grace period")
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	// warnings are dropped unless asked for
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+	if _, err := c.NewProvisionNamespace(testSigner, "alice.drinks"); err != nil {
+		t.Fatal(err)
+	}
+	if logged.Len() != 0 {
+		t.Fatalf("unexpected output %q", logged.String())
+	}
+}
@@ -16,10 +16,15 @@ package nemgo
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -56,6 +61,14 @@ func WithNIS(host string, network byte) Option {
 	}
 }
 
+// WithTLS makes the Client connect to its NIS over https, it must be
+// passed after WithNIS
+func WithTLS() Option {
+	return func(c *Client) {
+		c.url.Scheme = "https"
+	}
+}
+
 // New will return a Client object ready to be used
 // defaulting to the NEM mainnet
 func New(opts ...Option) *Client {
@@ -83,6 +96,9 @@ func (c Client) buildReq(params map[string]string, payload []byte, method string
 	if err != nil {
 		return req, err
 	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	return req, nil
 }
 
@@ -97,5 +113,60 @@ func sendReq(req *http.Request) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newNISError(resp.StatusCode, body)
+	}
 	return body, nil
 }
+
+// NISError is an error response of NIS, such as
+// {"timeStamp": 9232968, "error": "Not Found", "message": "invalid address", "status": 404}
+type NISError struct {
+	// TimeStamp is the network time of the error
+	TimeStamp NetworkTime `json:"timeStamp"`
+	// Status is the HTTP status code
+	Status int `json:"status"`
+	// Reason is the "error" field of the response, the text of the
+	// status code
+	Reason string `json:"error"`
+	// Message describes the error
+	Message string `json:"message"`
+}
+
+func (e *NISError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("NIS error %d %s", e.Status, e.Reason)
+	}
+	return fmt.Sprintf("NIS error %d %s: %s", e.Status, e.Reason, e.Message)
+}
+
+// newNISError decodes the body of a response with the given status code,
+// falling back on the body as message when it is not a NIS error
+func newNISError(status int, body []byte) *NISError {
+	var e NISError
+	if err := json.Unmarshal(body, &e); err != nil || e.Reason == "" {
+		e = NISError{Message: strings.TrimSpace(string(body))}
+	}
+	e.Status = status
+	if e.Reason == "" {
+		e.Reason = http.StatusText(status)
+	}
+	return &e
+}
+
+// IsNotFound reports whether err, or the error it wraps, is a NIS error
+// for something that does not exist
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsBadRequest reports whether err, or the error it wraps, is a NIS
+// error for an invalid request, such as a malformed address
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+func hasStatus(err error, status int) bool {
+	e, ok := errors.Cause(err).(*NISError)
+	return ok && e.Status == status
+}
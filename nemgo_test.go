@@ -15,11 +15,16 @@
 package nemgo
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestNewWithOptions(t *testing.T) {
@@ -55,6 +60,40 @@ func ExampleNew() {
 
 }
 
+func TestSendReqNISError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/account/get":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"timeStamp": 9232968, "error": "Bad Request", "message": "invalid address 'TALICE'", "status": 400}`))
+		case "/chain/height":
+			w.Write([]byte(blockHeight))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+	c := New(WithNIS(strings.TrimPrefix(s.URL, "http://"), Testnet))
+	_, err := c.AccountData(Address("TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS"))
+	want := &NISError{TimeStamp: 9232968, Status: 400, Reason: "Bad Request", Message: "invalid address 'TALICE'"}
+	if !reflect.DeepEqual(errors.Cause(err), want) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, err)
+	}
+	if !IsBadRequest(err) || IsNotFound(err) {
+		t.Fatalf("unexpected status of %v", err)
+	}
+	_, err = c.Score()
+	if !IsNotFound(err) || IsNotFound(errors.New("Not Found")) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", "a not found error", err)
+	}
+	if !IsNotFound(errors.Wrap(err, "wrapped")) {
+		t.Fatal("expected a wrapped NIS error to be found")
+	}
+	if h, err := c.Height(); err != nil || h != 12345 {
+		t.Fatalf("\nWanted: %v\n   Got: %v %v", 12345, h, err)
+	}
+}
+
 func sendReqMock(req *http.Request) ([]byte, error) {
 	switch req.URL.Path {
 	case "/account/batch":
@@ -81,6 +120,30 @@ func sendReqMock(req *http.Request) ([]byte, error) {
 		return []byte(namespaceInfo), nil
 	case "/account/transfers/incoming", "/account/transfers/outgoing", "/account/transfers/all":
 		return []byte(transactionMetadataPairArray), nil
+	case "/transaction/announce":
+		return []byte(nemAnnounceResult), nil
+	case "/namespace/mosaic/definition/page":
+		return []byte(mosaicDefinitionMetadataPairArray), nil
+	case "/account/unconfirmedTransactions":
+		return []byte(unconfirmedTransactions), nil
+	case "/time-sync/network-time":
+		return []byte(networkTime), nil
+	case "/transaction/get":
+		var data struct{ Data []TransactionMetadataPair }
+		if err := json.Unmarshal([]byte(transactionMetadataPairArray), &data); err != nil {
+			return nil, err
+		}
+		for _, pair := range data.Data {
+			if pair.Meta.Hash.Data == req.URL.Query().Get("hash") {
+				return json.Marshal(pair)
+			}
+		}
+		return nil, &NISError{Status: 404, Reason: "Not Found", Message: "invalid hash"}
+	case "/mosaic/supply":
+		if req.URL.Query().Get("mosaicId") == "alice.drinks:tokens" {
+			return []byte(mosaicSupplyTokens), nil
+		}
+		return []byte(mosaicSupply), nil
 	default:
 		return nil, nil
 	}
@@ -337,3 +400,207 @@ const transactionMetadataPairArray = `{
               }
        }]
 }`
+
+const nemAnnounceResult = `{
+       "innerTransactionHash": {},
+       "code": 1,
+       "type": 1,
+       "message": "SUCCESS",
+       "transactionHash": {
+              "data": "c1786437336da077cd572a27710c40c378610e8d33880bcb7bdb0a42e3d35586"
+       }
+}`
+
+const mosaicDefinitionMetadataPairArray = `{
+       "data": [{
+              "meta": {
+                     "id": 161
+              },
+              "mosaic": {
+                     "creator": "10cfe522fe23c015b8ab9ef1d4f6b6ca1de7a0f0d3f6fc5f6e1dca8c4a4d3de2",
+                     "description": "the best juice",
+                     "id": {
+                            "namespaceId": "alice.drinks",
+                            "name": "orange juice"
+                     },
+                     "properties": [{
+                            "name": "divisibility",
+                            "value": "3"
+                     },{
+                            "name": "initialSupply",
+                            "value": "1000000"
+                     },{
+                            "name": "supplyMutable",
+                            "value": "true"
+                     },{
+                            "name": "transferable",
+                            "value": "true"
+                     }],
+                     "levy": {}
+              }
+       },{
+              "meta": {
+                     "id": 162
+              },
+              "mosaic": {
+                     "creator": "10cfe522fe23c015b8ab9ef1d4f6b6ca1de7a0f0d3f6fc5f6e1dca8c4a4d3de2",
+                     "description": "drink tokens",
+                     "id": {
+                            "namespaceId": "alice.drinks",
+                            "name": "tokens"
+                     },
+                     "properties": [{
+                            "name": "divisibility",
+                            "value": "0"
+                     }],
+                     "levy": {
+                            "type": 1,
+                            "recipient": "TBCI2A67UQZAKCR6NS4JWAEICEIGEIM72G3MVW5S",
+                            "mosaicId": {
+                                   "namespaceId": "nem",
+                                   "name": "xem"
+                            },
+                            "fee": 10
+                     }
+              }
+       }]
+}`
+
+const mosaicSupply = `{
+       "mosaicId": {
+              "namespaceId": "alice.drinks",
+              "name": "orange juice"
+       },
+       "supply": 1000000
+}`
+
+const mosaicSupplyTokens = `{
+       "mosaicId": {
+              "namespaceId": "alice.drinks",
+              "name": "tokens"
+       },
+       "supply": 20000
+}`
+
+const networkTime = `{
+       "sendTimeStamp": 9232968211,
+       "receiveTimeStamp": 9232968466
+}`
+
+const unconfirmedTransactions = `{
+       "data": [{
+              "meta": {"data": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+              "transaction": {
+                     "timeStamp": 9232910,
+                     "fee": 150000,
+                     "type": 4100,
+                     "deadline": 9236510,
+                     "version": -1744830463,
+                     "signer": "b4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6",
+                     "signatures": [{
+                            "timeStamp": 9232950,
+                            "fee": 150000,
+                            "type": 4098,
+                            "deadline": 9236550,
+                            "version": -1744830463,
+                            "signer": "c4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6",
+                            "otherHash": {"data": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+                            "otherAccount": "TAOCVCRNLXEHMBUIMYAIO3TDBJ6T4SLAHLQDUZ3A"
+                     }],
+                     "otherTrans": {
+                            "timeStamp": 9232900,
+                            "amount": 1000000,
+                            "fee": 50000,
+                            "recipient": "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+                            "type": 257,
+                            "deadline": 9236500,
+                            "version": -1744830463,
+                            "signer": "a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1a"
+                     }
+              }
+       }, {
+              "meta": {"data": null},
+              "transaction": {
+                            "timeStamp": 9232900,
+                            "amount": 1000000,
+                            "fee": 50000,
+                            "recipient": "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+                            "type": 257,
+                            "deadline": 9236500,
+                            "version": -1744830463,
+                            "signer": "a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1a"
+                     }
+       }, {
+              "meta": {"data": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+              "transaction": {
+                     "timeStamp": 9232910,
+                     "fee": 150000,
+                     "type": 4100,
+                     "deadline": 9236510,
+                     "version": -1744830463,
+                     "signer": "c5f54ba980fcbb657dbaaa42700539b207873e134d2375efeab5f1ab52f87844",
+                     "signatures": [],
+                     "otherTrans": {
+                            "timeStamp": 9232900,
+                            "amount": 1000000,
+                            "fee": 50000,
+                            "recipient": "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+                            "type": 257,
+                            "deadline": 9236500,
+                            "version": -1744830463,
+                            "signer": "a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1a"
+                     }
+              }
+       }, {
+              "meta": {"data": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+              "transaction": {
+                     "timeStamp": 9232910,
+                     "fee": 150000,
+                     "type": 4100,
+                     "deadline": 9236510,
+                     "version": -1744830463,
+                     "signer": "b4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6",
+                     "signatures": [{
+                            "timeStamp": 9232950,
+                            "fee": 150000,
+                            "type": 4098,
+                            "deadline": 9236550,
+                            "version": -1744830463,
+                            "signer": "d4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6",
+                            "otherHash": {"data": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+                            "otherAccount": "TAOCVCRNLXEHMBUIMYAIO3TDBJ6T4SLAHLQDUZ3A"
+                     }],
+                     "otherTrans": {
+                            "timeStamp": 9232900,
+                            "amount": 1000000,
+                            "fee": 50000,
+                            "recipient": "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+                            "type": 257,
+                            "deadline": 9236500,
+                            "version": -1744830463,
+                            "signer": "a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1a"
+                     }
+              }
+       }, {
+              "meta": {"data": "cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"},
+              "transaction": {
+                     "timeStamp": 9232910,
+                     "fee": 150000,
+                     "type": 4100,
+                     "deadline": 9236510,
+                     "version": -1744830463,
+                     "signer": "c4e5d4b9d9c0d5e8c8b4b1f6f1e8e1f4c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6",
+                     "signatures": [],
+                     "otherTrans": {
+                            "timeStamp": 9232900,
+                            "amount": 1000000,
+                            "fee": 50000,
+                            "recipient": "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+                            "type": 257,
+                            "deadline": 9236500,
+                            "version": -1744830463,
+                            "signer": "a11a1a6c17a24252e674d151713cdf51991ad101751e4af02a20c61b59f1fe1a"
+                     }
+              }
+       }]
+}`
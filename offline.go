@@ -0,0 +1,216 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+// UnsignedTransaction is a transaction exported for signing on another,
+// possibly offline, machine. It is meant to be written as JSON.
+//
+// Data is what gets signed, the other fields describe it so the
+// transaction can be reviewed before signing. Decode checks that they
+// agree with Data.
+type UnsignedTransaction struct {
+	Network   byte        `json:"network"`
+	Type      int         `json:"type"`
+	Signer    string      `json:"signer"`
+	Fee       int64       `json:"fee"`
+	TimeStamp NetworkTime `json:"timeStamp"`
+	Deadline  NetworkTime `json:"deadline"`
+	// Expires is the deadline as a time in UTC
+	Expires string `json:"expires"`
+	// Transaction holds every field of the transaction
+	Transaction json.RawMessage `json:"transaction"`
+	// Data is the hex encoded serialized transaction
+	Data string `json:"data"`
+}
+
+// PrepareTransaction validates a transaction against the network time
+// and fees of the NIS and exports it for signing. The result can be
+// signed offline with UnsignedTransaction.Sign and the signed
+// transaction announced later with AnnounceSigned.
+func (c Client) PrepareTransaction(tx TransactionEntity) (UnsignedTransaction, error) {
+	if err := c.validateTransaction(tx); err != nil {
+		return UnsignedTransaction{}, err
+	}
+	return newUnsignedTransaction(tx)
+}
+
+func newUnsignedTransaction(tx TransactionEntity) (UnsignedTransaction, error) {
+	var u UnsignedTransaction
+	data, err := Serialize(tx)
+	if err != nil {
+		return u, err
+	}
+	fields, err := json.Marshal(tx)
+	if err != nil {
+		return u, err
+	}
+	c := tx.Common()
+	u = UnsignedTransaction{
+		Network:     c.Network(),
+		Type:        c.Type,
+		Signer:      c.Signer,
+		Fee:         c.Fee,
+		TimeStamp:   c.TimeStamp,
+		Deadline:    c.Deadline,
+		Expires:     c.Deadline.String(),
+		Transaction: fields,
+		Data:        hex.EncodeToString(data)}
+	return u, nil
+}
+
+// Decode returns the transaction in Data, after checking that every
+// other field describes it
+func (u UnsignedTransaction) Decode() (TransactionEntity, error) {
+	data, err := hex.DecodeString(u.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "data is not valid hex")
+	}
+	tx, err := Deserialize(data)
+	if err != nil {
+		return nil, err
+	}
+	want, err := newUnsignedTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	// the fields are compared by what they serialize to, so that nil and
+	// empty lists or the case of hex strings do not matter
+	fields, err := newTransaction(CommonTransaction{Type: tx.Common().Type})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(u.Transaction, fields); err != nil {
+		return nil, errors.Wrap(err, "invalid transaction fields")
+	}
+	fieldsData, err := Serialize(fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction fields")
+	}
+	switch {
+	case u.Network != want.Network:
+		return nil, errors.Errorf("network 0x%x does not match data", u.Network)
+	case u.Type != want.Type:
+		return nil, errors.Errorf("type 0x%x does not match data", u.Type)
+	case !strings.EqualFold(u.Signer, want.Signer):
+		return nil, errors.Errorf("signer %s does not match data", u.Signer)
+	case u.Fee != want.Fee:
+		return nil, errors.Errorf("fee %d does not match data", u.Fee)
+	case u.TimeStamp != want.TimeStamp || u.Deadline != want.Deadline || u.Expires != want.Expires:
+		return nil, errors.New("timestamp or deadline does not match data")
+	case !bytes.Equal(fieldsData, data):
+		return nil, errors.New("transaction fields do not match data")
+	}
+	return tx, nil
+}
+
+// Sign signs the transaction with kp without using the network. network
+// is the network the signer expects the transaction to be for and now
+// is the local time, used to reject expired transactions.
+//
+// The fee is checked against the lowest fee NIS could accept since the
+// supply of mosaics is not known offline, every mosaic is assumed to cost
+// a single fee unit.
+func (u UnsignedTransaction) Sign(kp crypto.KeyPair, network byte, now time.Time) (RequestAnnounce, error) {
+	tx, err := u.Decode()
+	if err != nil {
+		return RequestAnnounce{}, err
+	}
+	minFee, err := Fee(tx, unknownMosaicSupply)
+	if err != nil {
+		return RequestAnnounce{}, err
+	}
+	if err := validateTransaction(tx, network, NetworkTimeOf(now), minFee); err != nil {
+		return RequestAnnounce{}, err
+	}
+	return SignTransaction(kp, tx)
+}
+
+// AnnounceSigned announces a transaction signed offline, after checking
+// its signature, network, deadline and fee
+func (c Client) AnnounceSigned(ra RequestAnnounce) (NemAnnounceResult, error) {
+	data, err := hex.DecodeString(ra.Data)
+	if err != nil {
+		return NemAnnounceResult{}, errors.Wrap(err, "data is not valid hex")
+	}
+	sig, err := hex.DecodeString(ra.Signature)
+	if err != nil {
+		return NemAnnounceResult{}, errors.Wrap(err, "signature is not valid hex")
+	}
+	tx, err := Deserialize(data)
+	if err != nil {
+		return NemAnnounceResult{}, err
+	}
+	signer, err := hex.DecodeString(tx.Common().Signer)
+	if err != nil {
+		return NemAnnounceResult{}, err
+	}
+	if !crypto.Verify(signer, data, sig) {
+		return NemAnnounceResult{}, errors.New("invalid signature")
+	}
+	if err := c.validateTransaction(tx); err != nil {
+		return NemAnnounceResult{}, err
+	}
+	return c.Announce(ra)
+}
+
+// validateTransaction checks a transaction against the network, network
+// time and fees of the NIS
+func (c Client) validateTransaction(tx TransactionEntity) error {
+	now, err := c.NetworkTime()
+	if err != nil {
+		return errors.Wrap(err, "unable to get network time")
+	}
+	minFee, err := c.Fee(tx)
+	if err != nil {
+		return err
+	}
+	return validateTransaction(tx, c.network, now, minFee)
+}
+
+// validateTransaction checks that a transaction is for network, is
+// valid at now and pays at least minFee
+func validateTransaction(tx TransactionEntity, network byte, now NetworkTime, minFee int64) error {
+	c := tx.Common()
+	if c.Network() != network {
+		return errors.Errorf("transaction is for network 0x%x, expected 0x%x", c.Network(), network)
+	}
+	if c.Deadline <= now {
+		return errors.Errorf("transaction expired at %v", c.Deadline)
+	}
+	if c.Deadline > now.Add(maxDeadline) {
+		return errors.Errorf("deadline %v is more than %v away", c.Deadline, maxDeadline)
+	}
+	if c.Fee < minFee {
+		return errors.Errorf("fee %d is below the minimum of %d", c.Fee, minFee)
+	}
+	return nil
+}
+
+// unknownMosaicSupply is a MosaicSupplyFunc for when the supply of
+// mosaics cannot be looked up, it gives the lowest possible mosaic fee
+func unknownMosaicSupply(MosaicID) (int64, int, error) {
+	return 0, 0, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+// offlineNow is a minute after the network time of the NIS mock
+var offlineNow = NetworkTime(9232968 + 60).Time()
+
+func prepareOffline(t *testing.T, kp crypto.KeyPair, opts ...TxOption) []byte {
+	tx, err := clientMock.NewTransfer(PublicKey(kp.PublicKeyString()), "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := clientMock.PrepareTransaction(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blob
+}
+
+func TestOfflineSigning(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := prepareOffline(t, kp, WithMessage(NewPlainMessage("cold")))
+	var u UnsignedTransaction
+	if err := json.Unmarshal(blob, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Network != Testnet || u.Fee != 100000 || u.Expires != "2015-07-13T21:49:13Z" {
+		t.Fatalf("unexpected transaction %s", blob)
+	}
+	ra, err := u.Sign(kp, Testnet, offlineNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ra.Data != u.Data {
+		t.Fatalf("\nWanted: %v\n   Got: %v", u.Data, ra.Data)
+	}
+	got, err := clientMock.AnnounceSigned(ra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != 1 {
+		t.Fatalf("unexpected result %+v", got)
+	}
+	ra.Signature = strings.Repeat("00", 64)
+	if _, err := clientMock.AnnounceSigned(ra); err == nil {
+		t.Fatal("expected an error for an invalid signature")
+	}
+}
+
+func TestOfflineSigningInvalid(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u UnsignedTransaction
+	if err := json.Unmarshal(prepareOffline(t, kp), &u); err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, sign := range map[string]func(UnsignedTransaction) error{
+		"wrong network": func(u UnsignedTransaction) error {
+			_, err := u.Sign(kp, Mainnet, offlineNow)
+			return err
+		},
+		"expired": func(u UnsignedTransaction) error {
+			_, err := u.Sign(kp, Testnet, offlineNow.Add(2*time.Hour))
+			return err
+		},
+		"wrong key": func(u UnsignedTransaction) error {
+			_, err := u.Sign(other, Testnet, offlineNow)
+			return err
+		},
+		"tampered fee": func(u UnsignedTransaction) error {
+			u.Fee = 1
+			_, err := u.Sign(kp, Testnet, offlineNow)
+			return err
+		},
+		"tampered fields": func(u UnsignedTransaction) error {
+			u.Transaction = json.RawMessage(strings.Replace(string(u.Transaction), "TALICE", "TBOBBY", 1))
+			_, err := u.Sign(kp, Testnet, offlineNow)
+			return err
+		},
+		"tampered data": func(u UnsignedTransaction) error {
+			u.Data = u.Data[:len(u.Data)-2]
+			_, err := u.Sign(kp, Testnet, offlineNow)
+			return err
+		},
+	} {
+		if err := sign(u); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	tx, err := clientMock.NewTransfer(PublicKey(kp.PublicKeyString()), "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000, WithFee(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientMock.PrepareTransaction(&tx); err == nil {
+		t.Fatal("expected an error for a fee below the minimum")
+	}
+}
+
+func TestOfflineSigningEveryType(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := map[string]TransactionEntity{
+		// values that serialize the same as what Deserialize returns
+		"empty modifications": &MultisigAggregateModificationTransaction{
+			CommonTransaction: testCommon(MultisigAggregateModificationType, 2),
+			Modifications:     []CosignatoryModification{},
+			MinCosignatories:  1},
+		"uppercase hex": &TransferTransaction{
+			CommonTransaction: testCommon(TransferType, 1),
+			Recipient:         "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+			Message:           &Message{Payload: "ABCD", Type: MessageTypePlain}},
+	}
+	for _, f := range serializeFixtures {
+		data, err := hex.DecodeString(f.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if txs[f.name], err = Deserialize(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the NIS mock only knows the supply of alice.drinks:tokens
+	txs["transfer v2 with mosaics"].(*TransferTransaction).Mosaics[1].MosaicID.Name = "tokens"
+	for name, tx := range txs {
+		c := tx.Common()
+		c.Signer = strings.ToUpper(kp.PublicKeyString())
+		c.TimeStamp = 9232968
+		c.Deadline = c.TimeStamp.Add(time.Hour)
+		if c.Fee, err = clientMock.Fee(tx); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		u, err := clientMock.PrepareTransaction(tx)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		blob, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got UnsignedTransaction
+		if err := json.Unmarshal(blob, &got); err != nil {
+			t.Fatal(err)
+		}
+		ra, err := got.Sign(kp, Testnet, offlineNow)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := clientMock.AnnounceSigned(ra); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,775 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Transaction types as they appear in the Type field of a transaction
+const (
+	TransferType                      = 0x101
+	ImportanceTransferType            = 0x801
+	MultisigAggregateModificationType = 0x1001
+	MultisigSignatureType             = 0x1002
+	MultisigType                      = 0x1004
+	ProvisionNamespaceType            = 0x2001
+	MosaicDefinitionCreationType      = 0x4001
+	MosaicSupplyChangeType            = 0x4002
+)
+
+const (
+	// ImportanceTransferActivate is the mode activating a remote account
+	ImportanceTransferActivate = 1
+	// ImportanceTransferDeactivate is the mode deactivating a remote
+	// account
+	ImportanceTransferDeactivate = 2
+
+	// CosignatoryAdd is the modification type adding a cosignatory
+	CosignatoryAdd = 1
+	// CosignatoryDelete is the modification type removing a cosignatory
+	CosignatoryDelete = 2
+
+	// MosaicSupplyIncrease is the supply type creating new mosaics
+	MosaicSupplyIncrease = 1
+	// MosaicSupplyDecrease is the supply type destroying mosaics
+	MosaicSupplyDecrease = 2
+
+	// MosaicLevyAbsolute is the levy type taking a fixed quantity of
+	// the levy mosaic for every transfer
+	MosaicLevyAbsolute = 1
+	// MosaicLevyPercentile is the levy type taking a quantity of the
+	// levy mosaic proportional to the quantity transferred
+	MosaicLevyPercentile = 2
+)
+
+// CommonTransaction holds the fields every transaction starts with
+type CommonTransaction struct {
+	Type int
+	// Version holds the network byte in its most significant byte and the
+	// version of the transaction type in its least significant byte
+	Version int
+	// TimeStamp is the number of seconds since the nemesis block at
+	// which the transaction was created
+	TimeStamp NetworkTime
+	// Signer is the hex encoded public key of the account creating the
+	// transaction
+	Signer string
+	// Fee is the fee in micro XEM
+	Fee int64
+	// Deadline is the number of seconds since the nemesis block after
+	// which the transaction is rejected
+	Deadline NetworkTime
+}
+
+// Common returns the common part of the transaction
+func (c *CommonTransaction) Common() *CommonTransaction {
+	return c
+}
+
+// Network returns the network byte of the transaction
+func (c CommonTransaction) Network() byte {
+	return byte(uint32(c.Version) >> 24)
+}
+
+// TxVersion returns the version of the transaction type, without the
+// network byte
+func (c CommonTransaction) TxVersion() int {
+	return int(uint32(c.Version) & 0xff)
+}
+
+// TransactionEntity is a transaction that can be serialized into the
+// binary format NIS signs and hashes
+type TransactionEntity interface {
+	Common() *CommonTransaction
+	writeBody(w *writer) error
+	readBody(r *reader)
+}
+
+// MosaicID identifies a mosaic by its namespace and name
+type MosaicID struct {
+	NamespaceID string
+	Name        string
+}
+
+// String returns the fully qualified name of the mosaic, namespace:name
+func (m MosaicID) String() string {
+	return m.NamespaceID + ":" + m.Name
+}
+
+// Mosaic is a quantity of a mosaic, in its smallest unit
+type Mosaic struct {
+	MosaicID MosaicID
+	Quantity int64
+}
+
+// TransferTransaction sends XEM, and optionally a message and mosaics, to
+// a recipient
+type TransferTransaction struct {
+	CommonTransaction
+	Recipient string
+	// Amount is the amount of micro XEM to send for a version 1
+	// transfer and the multiplier of the mosaics for a version 2 transfer
+	Amount int64
+	// Message is attached to the transfer when it is not nil
+	Message *Message
+	// Mosaics are only serialized for version 2 transfers
+	Mosaics []Mosaic
+}
+
+// ImportanceTransferTransaction activates or deactivates a remote account
+// harvesting on behalf of the signer
+type ImportanceTransferTransaction struct {
+	CommonTransaction
+	// Mode is ImportanceTransferActivate or ImportanceTransferDeactivate
+	Mode int
+	// RemoteAccount is the hex encoded public key of the remote account
+	RemoteAccount string
+}
+
+// CosignatoryModification adds or removes a cosignatory of a multisig
+// account
+type CosignatoryModification struct {
+	// ModificationType is CosignatoryAdd or CosignatoryDelete
+	ModificationType int
+	// CosignatoryAccount is the hex encoded public key of the cosignatory
+	CosignatoryAccount string
+}
+
+// MultisigAggregateModificationTransaction converts an account into a
+// multisig account or changes its cosignatories
+type MultisigAggregateModificationTransaction struct {
+	CommonTransaction
+	Modifications []CosignatoryModification
+	// MinCosignatories is the relative change of the minimum number of
+	// cosignatories, it is only serialized for version 2
+	MinCosignatories int
+}
+
+// MultisigSignatureTransaction is a cosignatory's signature of a pending
+// multisig transaction
+type MultisigSignatureTransaction struct {
+	CommonTransaction
+	// OtherHash is the hex encoded hash of the inner transaction
+	OtherHash string
+	// OtherAccount is the address of the multisig account
+	OtherAccount string
+}
+
+// MultisigTransaction wraps a transaction on behalf of a multisig account
+type MultisigTransaction struct {
+	CommonTransaction
+	OtherTrans TransactionEntity
+}
+
+// ProvisionNamespaceTransaction registers or renews a namespace
+type ProvisionNamespaceTransaction struct {
+	CommonTransaction
+	RentalFeeSink string
+	// RentalFee is the rental fee in micro XEM
+	RentalFee int64
+	NewPart   string
+	// Parent is empty for root namespaces
+	Parent string
+}
+
+// MosaicProperty is a property of a mosaic definition
+type MosaicProperty struct {
+	Name  string
+	Value string
+}
+
+// MosaicLevy is a fee paid to the mosaic creator on every transfer of
+// the mosaic
+type MosaicLevy struct {
+	// Type is MosaicLevyAbsolute or MosaicLevyPercentile
+	Type      int
+	Recipient string
+	MosaicID  MosaicID
+	Fee       int64
+}
+
+// MosaicDefinition describes a mosaic
+type MosaicDefinition struct {
+	// Creator is the hex encoded public key of the creator
+	Creator     string
+	ID          MosaicID
+	Description string
+	Properties  []MosaicProperty
+	// Levy is nil when the mosaic has no levy
+	Levy *MosaicLevy
+}
+
+// MosaicDefinitionCreationTransaction creates or changes a mosaic
+// definition
+type MosaicDefinitionCreationTransaction struct {
+	CommonTransaction
+	MosaicDefinition MosaicDefinition
+	CreationFeeSink  string
+	// CreationFee is the creation fee in micro XEM
+	CreationFee int64
+}
+
+// MosaicSupplyChangeTransaction increases or decreases the supply of a
+// mosaic
+type MosaicSupplyChangeTransaction struct {
+	CommonTransaction
+	MosaicID MosaicID
+	// SupplyType is MosaicSupplyIncrease or MosaicSupplyDecrease
+	SupplyType int
+	// Delta is the change of supply in whole units of the mosaic
+	Delta int64
+}
+
+// version returns the Version field of a transaction on a network
+func version(network byte, v int) int {
+	return int(int32(uint32(network)<<24 | uint32(v)))
+}
+
+// Serialize returns the transaction in the NIS binary format.
+// This is the data that is signed and announced to NIS.
+func Serialize(tx TransactionEntity) ([]byte, error) {
+	w := &writer{}
+	c := tx.Common()
+	w.uint32(uint32(c.Type))
+	w.uint32(uint32(c.Version))
+	w.uint32(uint32(c.TimeStamp))
+	if err := w.hexBytes(c.Signer, 32); err != nil {
+		return nil, errors.Wrap(err, "signer")
+	}
+	w.int64(c.Fee)
+	w.uint32(uint32(c.Deadline))
+	if err := tx.writeBody(w); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// Deserialize parses a transaction in the NIS binary format.
+// The concrete type of the result depends on the transaction type, a
+// transfer is returned as a *TransferTransaction and so on.
+func Deserialize(data []byte) (TransactionEntity, error) {
+	r := &reader{b: data}
+	tx, err := readTransaction(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.b) != 0 {
+		return nil, errors.Errorf("%d unexpected bytes after transaction", len(r.b))
+	}
+	return tx, nil
+}
+
+func readTransaction(r *reader) (TransactionEntity, error) {
+	var c CommonTransaction
+	c.Type = int(r.uint32())
+	c.Version = int(int32(r.uint32()))
+	c.TimeStamp = NetworkTime(r.uint32())
+	c.Signer = r.hexBytes(32)
+	c.Fee = r.int64()
+	c.Deadline = NetworkTime(r.uint32())
+	if r.err != nil {
+		return nil, r.err
+	}
+	tx, err := newTransaction(c)
+	if err != nil {
+		return nil, err
+	}
+	tx.readBody(r)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return tx, nil
+}
+
+// newTransaction returns a transaction of the concrete type for c.Type
+// holding c
+func newTransaction(c CommonTransaction) (TransactionEntity, error) {
+	switch c.Type {
+	case TransferType:
+		return &TransferTransaction{CommonTransaction: c}, nil
+	case ImportanceTransferType:
+		return &ImportanceTransferTransaction{CommonTransaction: c}, nil
+	case MultisigAggregateModificationType:
+		return &MultisigAggregateModificationTransaction{CommonTransaction: c}, nil
+	case MultisigSignatureType:
+		return &MultisigSignatureTransaction{CommonTransaction: c}, nil
+	case MultisigType:
+		return &MultisigTransaction{CommonTransaction: c}, nil
+	case ProvisionNamespaceType:
+		return &ProvisionNamespaceTransaction{CommonTransaction: c}, nil
+	case MosaicDefinitionCreationType:
+		return &MosaicDefinitionCreationTransaction{CommonTransaction: c}, nil
+	case MosaicSupplyChangeType:
+		return &MosaicSupplyChangeTransaction{CommonTransaction: c}, nil
+	default:
+		return nil, errors.Errorf("unknown transaction type 0x%x", c.Type)
+	}
+}
+
+func (t *TransferTransaction) writeBody(w *writer) error {
+	if err := w.address(t.Recipient); err != nil {
+		return errors.Wrap(err, "recipient")
+	}
+	w.int64(t.Amount)
+	if err := w.message(t.Message); err != nil {
+		return err
+	}
+	if t.TxVersion() < 2 {
+		if len(t.Mosaics) > 0 {
+			return errors.New("mosaics require a version 2 transfer")
+		}
+		return nil
+	}
+	w.uint32(uint32(len(t.Mosaics)))
+	for _, m := range t.Mosaics {
+		var sub writer
+		sub.mosaicID(m.MosaicID)
+		sub.int64(m.Quantity)
+		w.bytes(sub.Bytes())
+	}
+	return nil
+}
+
+func (t *TransferTransaction) readBody(r *reader) {
+	t.Recipient = r.address()
+	t.Amount = r.int64()
+	t.Message = r.message()
+	if t.TxVersion() < 2 {
+		return
+	}
+	n := r.count()
+	for i := 0; i < n && r.err == nil; i++ {
+		sub := r.sub()
+		m := Mosaic{MosaicID: sub.mosaicID(), Quantity: sub.int64()}
+		r.end(sub)
+		t.Mosaics = append(t.Mosaics, m)
+	}
+}
+
+func (t *ImportanceTransferTransaction) writeBody(w *writer) error {
+	w.uint32(uint32(t.Mode))
+	return errors.Wrap(w.hexBytes(t.RemoteAccount, 32), "remote account")
+}
+
+func (t *ImportanceTransferTransaction) readBody(r *reader) {
+	t.Mode = int(r.uint32())
+	t.RemoteAccount = r.hexBytes(32)
+}
+
+func (t *MultisigAggregateModificationTransaction) writeBody(w *writer) error {
+	w.uint32(uint32(len(t.Modifications)))
+	for _, m := range t.Modifications {
+		var sub writer
+		sub.uint32(uint32(m.ModificationType))
+		if err := sub.hexBytes(m.CosignatoryAccount, 32); err != nil {
+			return errors.Wrap(err, "cosignatory")
+		}
+		w.bytes(sub.Bytes())
+	}
+	if t.TxVersion() < 2 {
+		return nil
+	}
+	if t.MinCosignatories == 0 {
+		w.uint32(0)
+		return nil
+	}
+	w.uint32(4)
+	w.uint32(uint32(int32(t.MinCosignatories)))
+	return nil
+}
+
+func (t *MultisigAggregateModificationTransaction) readBody(r *reader) {
+	n := r.count()
+	for i := 0; i < n && r.err == nil; i++ {
+		sub := r.sub()
+		m := CosignatoryModification{
+			ModificationType:   int(sub.uint32()),
+			CosignatoryAccount: sub.hexBytes(32)}
+		r.end(sub)
+		t.Modifications = append(t.Modifications, m)
+	}
+	if t.TxVersion() < 2 {
+		return
+	}
+	sub := r.sub()
+	if len(sub.b) > 0 {
+		t.MinCosignatories = int(int32(sub.uint32()))
+	}
+	r.end(sub)
+}
+
+func (t *MultisigSignatureTransaction) writeBody(w *writer) error {
+	var sub writer
+	if err := sub.hexBytes(t.OtherHash, 32); err != nil {
+		return errors.Wrap(err, "hash")
+	}
+	w.bytes(sub.Bytes())
+	return errors.Wrap(w.address(t.OtherAccount), "multisig account")
+}
+
+func (t *MultisigSignatureTransaction) readBody(r *reader) {
+	sub := r.sub()
+	t.OtherHash = sub.hexBytes(32)
+	r.end(sub)
+	t.OtherAccount = r.address()
+}
+
+func (t *MultisigTransaction) writeBody(w *writer) error {
+	if t.OtherTrans == nil {
+		return errors.New("multisig transaction has no inner transaction")
+	}
+	inner, err := Serialize(t.OtherTrans)
+	if err != nil {
+		return errors.Wrap(err, "inner transaction")
+	}
+	w.bytes(inner)
+	return nil
+}
+
+func (t *MultisigTransaction) readBody(r *reader) {
+	sub := r.sub()
+	if r.err != nil {
+		return
+	}
+	inner, err := readTransaction(sub)
+	if err != nil {
+		r.err = errors.Wrap(err, "inner transaction")
+		return
+	}
+	t.OtherTrans = inner
+	r.end(sub)
+}
+
+// UnmarshalJSON reads OtherTrans as the type of transaction it holds
+func (t *MultisigTransaction) UnmarshalJSON(data []byte) error {
+	var v struct {
+		CommonTransaction
+		OtherTrans json.RawMessage
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	t.CommonTransaction, t.OtherTrans = v.CommonTransaction, nil
+	if len(v.OtherTrans) == 0 || string(v.OtherTrans) == "null" {
+		return nil
+	}
+	var c CommonTransaction
+	if err := json.Unmarshal(v.OtherTrans, &c); err != nil {
+		return err
+	}
+	inner, err := newTransaction(CommonTransaction{Type: c.Type})
+	if err != nil {
+		return errors.Wrap(err, "inner transaction")
+	}
+	if err := json.Unmarshal(v.OtherTrans, inner); err != nil {
+		return err
+	}
+	t.OtherTrans = inner
+	return nil
+}
+
+func (t *ProvisionNamespaceTransaction) writeBody(w *writer) error {
+	if err := w.address(t.RentalFeeSink); err != nil {
+		return errors.Wrap(err, "rental fee sink")
+	}
+	w.int64(t.RentalFee)
+	w.bytes([]byte(t.NewPart))
+	if t.Parent == "" {
+		w.uint32(0xffffffff)
+		return nil
+	}
+	w.bytes([]byte(t.Parent))
+	return nil
+}
+
+func (t *ProvisionNamespaceTransaction) readBody(r *reader) {
+	t.RentalFeeSink = r.address()
+	t.RentalFee = r.int64()
+	t.NewPart = string(r.bytes())
+	if r.peekUint32() == 0xffffffff {
+		r.uint32()
+		return
+	}
+	t.Parent = string(r.bytes())
+}
+
+func (t *MosaicDefinitionCreationTransaction) writeBody(w *writer) error {
+	d := t.MosaicDefinition
+	var def writer
+	if err := def.hexBytes(d.Creator, 32); err != nil {
+		return errors.Wrap(err, "creator")
+	}
+	def.mosaicID(d.ID)
+	def.bytes([]byte(d.Description))
+	def.uint32(uint32(len(d.Properties)))
+	for _, p := range d.Properties {
+		var sub writer
+		sub.bytes([]byte(p.Name))
+		sub.bytes([]byte(p.Value))
+		def.bytes(sub.Bytes())
+	}
+	if d.Levy == nil {
+		def.uint32(0)
+	} else {
+		var levy writer
+		levy.uint32(uint32(d.Levy.Type))
+		if err := levy.address(d.Levy.Recipient); err != nil {
+			return errors.Wrap(err, "levy recipient")
+		}
+		levy.mosaicID(d.Levy.MosaicID)
+		levy.int64(d.Levy.Fee)
+		def.bytes(levy.Bytes())
+	}
+	w.bytes(def.Bytes())
+	if err := w.address(t.CreationFeeSink); err != nil {
+		return errors.Wrap(err, "creation fee sink")
+	}
+	w.int64(t.CreationFee)
+	return nil
+}
+
+func (t *MosaicDefinitionCreationTransaction) readBody(r *reader) {
+	def := r.sub()
+	d := &t.MosaicDefinition
+	d.Creator = def.hexBytes(32)
+	d.ID = def.mosaicID()
+	d.Description = string(def.bytes())
+	n := def.count()
+	for i := 0; i < n && def.err == nil; i++ {
+		sub := def.sub()
+		p := MosaicProperty{Name: string(sub.bytes()), Value: string(sub.bytes())}
+		def.end(sub)
+		d.Properties = append(d.Properties, p)
+	}
+	levy := def.sub()
+	if len(levy.b) > 0 {
+		d.Levy = &MosaicLevy{
+			Type:      int(levy.uint32()),
+			Recipient: levy.address(),
+			MosaicID:  levy.mosaicID(),
+			Fee:       levy.int64()}
+	}
+	def.end(levy)
+	r.end(def)
+	t.CreationFeeSink = r.address()
+	t.CreationFee = r.int64()
+}
+
+func (t *MosaicSupplyChangeTransaction) writeBody(w *writer) error {
+	w.mosaicID(t.MosaicID)
+	w.uint32(uint32(t.SupplyType))
+	w.int64(t.Delta)
+	return nil
+}
+
+func (t *MosaicSupplyChangeTransaction) readBody(r *reader) {
+	t.MosaicID = r.mosaicID()
+	t.SupplyType = int(r.uint32())
+	t.Delta = r.int64()
+}
+
+// writer writes the little endian, length prefixed fields NIS uses
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) uint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func (w *writer) int64(v int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	w.Write(b[:])
+}
+
+// bytes writes a length prefixed byte array
+func (w *writer) bytes(b []byte) {
+	w.uint32(uint32(len(b)))
+	w.Write(b)
+}
+
+// hexBytes writes a length prefixed, hex encoded byte array of size bytes
+func (w *writer) hexBytes(s string, size int) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return errors.Wrap(err, "not valid hex")
+	}
+	if len(b) != size {
+		return errors.Errorf("must be %d bytes, got %d", size, len(b))
+	}
+	w.bytes(b)
+	return nil
+}
+
+func (w *writer) address(a string) error {
+	if len(a) != 40 {
+		return errors.Errorf("address must be 40 characters, got %d", len(a))
+	}
+	w.bytes([]byte(a))
+	return nil
+}
+
+// message writes an optional message, an empty message is written as
+// no message at all
+func (w *writer) message(m *Message) error {
+	if m == nil || m.Payload == "" {
+		w.uint32(0)
+		return nil
+	}
+	payload, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+	w.uint32(uint32(8 + len(payload)))
+	w.uint32(uint32(m.Type))
+	w.bytes(payload)
+	return nil
+}
+
+// mosaicID writes a length prefixed mosaic id structure
+func (w *writer) mosaicID(id MosaicID) {
+	var sub writer
+	sub.bytes([]byte(id.NamespaceID))
+	sub.bytes([]byte(id.Name))
+	w.bytes(sub.Bytes())
+}
+
+// reader reads the fields written by writer. The first error is kept
+// and every later read returns a zero value, so callers only need to
+// check err once they are done.
+type reader struct {
+	b   []byte
+	err error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.b) < n {
+		r.err = errors.New("unexpected end of transaction data")
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *reader) peekUint32() uint32 {
+	if r.err != nil || len(r.b) < 4 {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(r.b)
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (r *reader) int64() int64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(b))
+}
+
+// count reads the number of elements of an array
+func (r *reader) count() int {
+	n := r.uint32()
+	if int64(n) > int64(len(r.b)) {
+		// every element takes at least one byte
+		r.err = errors.New("array length exceeds transaction data")
+		return 0
+	}
+	return int(n)
+}
+
+// bytes reads a length prefixed byte array
+func (r *reader) bytes() []byte {
+	n := r.uint32()
+	if int64(n) > int64(len(r.b)) {
+		r.next(len(r.b) + 1)
+		return nil
+	}
+	return r.next(int(n))
+}
+
+// sub reads a length prefixed structure
+func (r *reader) sub() *reader {
+	b := r.bytes()
+	return &reader{b: b, err: r.err}
+}
+
+// end checks that a structure read with sub was read completely
+func (r *reader) end(sub *reader) {
+	if r.err != nil {
+		return
+	}
+	if sub.err != nil {
+		r.err = sub.err
+		return
+	}
+	if len(sub.b) != 0 {
+		r.err = errors.Errorf("%d unexpected bytes in structure", len(sub.b))
+	}
+}
+
+func (r *reader) hexBytes(size int) string {
+	b := r.bytes()
+	if r.err == nil && len(b) != size {
+		r.err = errors.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return hex.EncodeToString(b)
+}
+
+func (r *reader) address() string {
+	b := r.bytes()
+	if r.err == nil && len(b) != 40 {
+		r.err = errors.Errorf("address must be 40 characters, got %d", len(b))
+	}
+	return string(b)
+}
+
+func (r *reader) message() *Message {
+	sub := r.sub()
+	if r.err != nil || len(sub.b) == 0 {
+		return nil
+	}
+	m := &Message{Type: int(sub.uint32())}
+	m.Payload = hex.EncodeToString(sub.bytes())
+	r.end(sub)
+	return m
+}
+
+func (r *reader) mosaicID() MosaicID {
+	sub := r.sub()
+	id := MosaicID{NamespaceID: string(sub.bytes()), Name: string(sub.bytes())}
+	r.end(sub)
+	return id
+}
@@ -0,0 +1,301 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSigner = "c5f54ba980fcbb657dbaaa42700539b207873e134d2375efeab5f1ab52f87844"
+
+func testCommon(txType int, v int) CommonTransaction {
+	return CommonTransaction{
+		Type:      txType,
+		Version:   version(Testnet, v),
+		TimeStamp: 1000,
+		Signer:    testSigner,
+		Fee:       50000,
+		Deadline:  4600}
+}
+
+// commonHex is testCommon serialized, with the type and version left out
+const commonHex = "e8030000" + "20000000" + testSigner + "50c3000000000000" + "f8110000"
+
+func TestSerializeTransfer(t *testing.T) {
+	m := NewPlainMessage("Robbery!!!")
+	tx := &TransferTransaction{
+		CommonTransaction: testCommon(TransferType, 1),
+		Recipient:         "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+		Amount:            1000000,
+		Message:           &m}
+	want := "01010000" + "01000098" + commonHex +
+		"28000000" + hex.EncodeToString([]byte("TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS")) +
+		"40420f0000000000" +
+		"12000000" + "01000000" + "0a000000" + "526f6262657279212121"
+	got, err := Serialize(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(got) != want {
+		t.Fatalf("\nWanted: %v\n   Got: %x", want, got)
+	}
+	// without a message the message field is only its zero length
+	tx.Message = nil
+	got, err = Serialize(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = want[:strings.Index(want, "12000000")] + "00000000"
+	if hex.EncodeToString(got) != want {
+		t.Fatalf("\nWanted: %v\n   Got: %x", want, got)
+	}
+}
+
+func TestSerializeInvalid(t *testing.T) {
+	tx := &TransferTransaction{
+		CommonTransaction: testCommon(TransferType, 1),
+		Recipient:         "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS"}
+	tx.Signer = "abcd"
+	if _, err := Serialize(tx); err == nil {
+		t.Fatal("expected an error for a short signer")
+	}
+	tx.Signer = testSigner
+	tx.Recipient = "TALICE"
+	if _, err := Serialize(tx); err == nil {
+		t.Fatal("expected an error for a short recipient")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if v := version(Testnet, 1); v != -1744830463 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", -1744830463, v)
+	}
+	if v := version(Mainnet, 2); v != 1744830466 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 1744830466, v)
+	}
+	c := CommonTransaction{Version: version(Testnet, 1)}
+	if c.Network() != Testnet {
+		t.Fatalf("\nWanted: %v\n   Got: %v", Testnet, c.Network())
+	}
+}
+
+// The fixtures below are laid out field by field following the NIS
+// binary format, each line is one field
+
+var (
+	addrHex   = hex.EncodeToString([]byte("TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS"))
+	remoteKey = "96eb2a145211b1b7ab5f0d4b14f8abc8d695c7aee31a3cfc2d4881313c68eea3"
+	innerHash = "15c373ad4c3fe6af47d1941379ff262f785bdcfa07c02ac3608bc10da27d5e82"
+)
+
+var serializeFixtures = []struct {
+	name string
+	tx   TransactionEntity
+	hex  string
+}{
+	{"transfer v2 with mosaics",
+		&TransferTransaction{
+			CommonTransaction: testCommon(TransferType, 2),
+			Recipient:         "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+			Amount:            1000000,
+			Message:           &Message{Payload: "fedeadbeef", Type: MessageTypePlain},
+			Mosaics: []Mosaic{
+				{MosaicID: MosaicID{NamespaceID: "nem", Name: "xem"}, Quantity: 5000000},
+				{MosaicID: MosaicID{NamespaceID: "alice.drinks", Name: "juice"}, Quantity: 7}}},
+		"01010000" + "02000098" + commonHex +
+			"28000000" + addrHex +
+			"40420f0000000000" +
+			"0d000000" + "01000000" + "05000000" + "fedeadbeef" +
+			"02000000" +
+			"1a000000" + "0e000000" + "03000000" + "6e656d" + "03000000" + "78656d" + "404b4c0000000000" +
+			"25000000" + "19000000" + "0c000000" + hex.EncodeToString([]byte("alice.drinks")) + "05000000" + hex.EncodeToString([]byte("juice")) + "0700000000000000"},
+	{"importance transfer",
+		&ImportanceTransferTransaction{
+			CommonTransaction: testCommon(ImportanceTransferType, 1),
+			Mode:              ImportanceTransferActivate,
+			RemoteAccount:     remoteKey},
+		"01080000" + "01000098" + commonHex +
+			"01000000" +
+			"20000000" + remoteKey},
+	{"multisig aggregate modification v2",
+		&MultisigAggregateModificationTransaction{
+			CommonTransaction: testCommon(MultisigAggregateModificationType, 2),
+			Modifications: []CosignatoryModification{
+				{ModificationType: CosignatoryAdd, CosignatoryAccount: remoteKey},
+				{ModificationType: CosignatoryDelete, CosignatoryAccount: testSigner}},
+			MinCosignatories: -1},
+		"01100000" + "02000098" + commonHex +
+			"02000000" +
+			"28000000" + "01000000" + "20000000" + remoteKey +
+			"28000000" + "02000000" + "20000000" + testSigner +
+			"04000000" + "ffffffff"},
+	{"multisig aggregate modification v1",
+		&MultisigAggregateModificationTransaction{
+			CommonTransaction: testCommon(MultisigAggregateModificationType, 1),
+			Modifications: []CosignatoryModification{
+				{ModificationType: CosignatoryAdd, CosignatoryAccount: remoteKey}}},
+		"01100000" + "01000098" + commonHex +
+			"01000000" +
+			"28000000" + "01000000" + "20000000" + remoteKey},
+	{"multisig signature",
+		&MultisigSignatureTransaction{
+			CommonTransaction: testCommon(MultisigSignatureType, 1),
+			OtherHash:         innerHash,
+			OtherAccount:      "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS"},
+		"02100000" + "01000098" + commonHex +
+			"24000000" + "20000000" + innerHash +
+			"28000000" + addrHex},
+	{"multisig",
+		&MultisigTransaction{
+			CommonTransaction: testCommon(MultisigType, 1),
+			OtherTrans: &ImportanceTransferTransaction{
+				CommonTransaction: testCommon(ImportanceTransferType, 1),
+				Mode:              ImportanceTransferDeactivate,
+				RemoteAccount:     remoteKey}},
+		"04100000" + "01000098" + commonHex +
+			"64000000" +
+			"01080000" + "01000098" + commonHex +
+			"02000000" +
+			"20000000" + remoteKey},
+	{"provision root namespace",
+		&ProvisionNamespaceTransaction{
+			CommonTransaction: testCommon(ProvisionNamespaceType, 1),
+			RentalFeeSink:     "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+			RentalFee:         100000000,
+			NewPart:           "alice"},
+		"01200000" + "01000098" + commonHex +
+			"28000000" + addrHex +
+			"00e1f50500000000" +
+			"05000000" + "616c696365" +
+			"ffffffff"},
+	{"provision sub namespace",
+		&ProvisionNamespaceTransaction{
+			CommonTransaction: testCommon(ProvisionNamespaceType, 1),
+			RentalFeeSink:     "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+			RentalFee:         10000000,
+			NewPart:           "drinks",
+			Parent:            "alice"},
+		"01200000" + "01000098" + commonHex +
+			"28000000" + addrHex +
+			"8096980000000000" +
+			"06000000" + "6472696e6b73" +
+			"05000000" + "616c696365"},
+	{"mosaic definition creation with levy",
+		&MosaicDefinitionCreationTransaction{
+			CommonTransaction: testCommon(MosaicDefinitionCreationType, 1),
+			MosaicDefinition: MosaicDefinition{
+				Creator:     testSigner,
+				ID:          MosaicID{NamespaceID: "alice", Name: "pts"},
+				Description: "points",
+				Properties: []MosaicProperty{
+					{Name: "divisibility", Value: "0"},
+					{Name: "transferable", Value: "true"}},
+				Levy: &MosaicLevy{
+					Type:      MosaicLevyAbsolute,
+					Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+					MosaicID:  MosaicID{NamespaceID: "nem", Name: "xem"},
+					Fee:       10}},
+			CreationFeeSink: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+			CreationFee:     10000000},
+		"01400000" + "01000098" + commonHex +
+			"c9000000" +
+			"20000000" + testSigner +
+			"10000000" + "05000000" + "616c696365" + "03000000" + "707473" +
+			"06000000" + "706f696e7473" +
+			"02000000" +
+			"15000000" + "0c000000" + hex.EncodeToString([]byte("divisibility")) + "01000000" + "30" +
+			"18000000" + "0c000000" + hex.EncodeToString([]byte("transferable")) + "04000000" + "74727565" +
+			"4a000000" + "01000000" + "28000000" + addrHex + "0e000000" + "03000000" + "6e656d" + "03000000" + "78656d" + "0a00000000000000" +
+			"28000000" + addrHex +
+			"8096980000000000"},
+	{"mosaic supply change",
+		&MosaicSupplyChangeTransaction{
+			CommonTransaction: testCommon(MosaicSupplyChangeType, 1),
+			MosaicID:          MosaicID{NamespaceID: "alice", Name: "pts"},
+			SupplyType:        MosaicSupplyDecrease,
+			Delta:             500},
+		"02400000" + "01000098" + commonHex +
+			"10000000" + "05000000" + "616c696365" + "03000000" + "707473" +
+			"02000000" +
+			"f401000000000000"},
+}
+
+func TestSerializeFixtures(t *testing.T) {
+	for _, f := range serializeFixtures {
+		got, err := Serialize(f.tx)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if hex.EncodeToString(got) != f.hex {
+			t.Fatalf("%s\nWanted: %v\n   Got: %x", f.name, f.hex, got)
+		}
+	}
+}
+
+func TestDeserializeFixtures(t *testing.T) {
+	for _, f := range serializeFixtures {
+		data, err := hex.DecodeString(f.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, err := Deserialize(data)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !reflect.DeepEqual(tx, f.tx) {
+			t.Fatalf("%s\nWanted: %+v\n   Got: %+v", f.name, f.tx, tx)
+		}
+		again, err := Serialize(tx)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(again, data) {
+			t.Fatalf("%s: round trip changed the data\nWanted: %x\n   Got: %x", f.name, data, again)
+		}
+	}
+}
+
+func TestDeserializeInvalid(t *testing.T) {
+	data, err := hex.DecodeString(serializeFixtures[0].hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(data); i += 7 {
+		if _, err := Deserialize(data[:i]); err == nil {
+			t.Fatalf("expected an error for data truncated to %d bytes", i)
+		}
+	}
+	if _, err := Deserialize(append(data, 0)); err == nil {
+		t.Fatal("expected an error for trailing data")
+	}
+	unknown := append([]byte{0x99, 0x99, 0, 0}, data[4:]...)
+	if _, err := Deserialize(unknown); err == nil {
+		t.Fatal("expected an error for an unknown transaction type")
+	}
+}
+
+func TestSerializeMosaicsRequireVersion2(t *testing.T) {
+	tx := &TransferTransaction{
+		CommonTransaction: testCommon(TransferType, 1),
+		Recipient:         "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
+		Mosaics:           []Mosaic{{MosaicID: MosaicID{NamespaceID: "nem", Name: "xem"}, Quantity: 1}}}
+	if _, err := Serialize(tx); err == nil {
+		t.Fatal("expected an error for mosaics in a version 1 transfer")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Nemesis is the time of the NEM nemesis block, network time is counted
+// in seconds from it
+var Nemesis = time.Date(2015, time.March, 29, 0, 6, 25, 0, time.UTC)
+
+// NetworkTime is a number of seconds since the nemesis block. NIS uses it
+// for the timestamps of blocks, harvests and transactions, and for the
+// deadlines of transactions.
+type NetworkTime int
+
+// NetworkTimeOf converts a time to network time, truncated to the second
+func NetworkTimeOf(t time.Time) NetworkTime {
+	return NetworkTime(t.Sub(Nemesis) / time.Second)
+}
+
+// Time converts the network time to a time in UTC
+func (n NetworkTime) Time() time.Time {
+	return Nemesis.Add(time.Duration(n) * time.Second)
+}
+
+// Add returns the network time d later, truncated to the second
+func (n NetworkTime) Add(d time.Duration) NetworkTime {
+	return n + NetworkTime(d/time.Second)
+}
+
+// String formats the network time as a time in UTC
+func (n NetworkTime) String() string {
+	return n.Time().Format(time.RFC3339)
+}
+
+// NetworkTime will get the current network time of the NIS. NEM nodes
+// keep their network time in sync, so it should be used for transactions
+// instead of the local clock which may be off.
+func (c Client) NetworkTime() (NetworkTime, error) {
+	var data struct {
+		// both are in milliseconds since the nemesis block
+		SendTimeStamp    int64
+		ReceiveTimeStamp int64
+	}
+	c.url.Path = "/time-sync/network-time"
+	req, err := c.buildReq(nil, nil, http.MethodGet)
+	if err != nil {
+		return 0, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return 0, err
+	}
+	return NetworkTime(data.ReceiveTimeStamp / 1000), nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNetworkTimeConversion(t *testing.T) {
+	if got := NetworkTimeOf(Nemesis); got != 0 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 0, int(got))
+	}
+	ts := time.Date(2015, time.March, 30, 0, 6, 25, 0, time.UTC)
+	if got := NetworkTimeOf(ts); got != 86400 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 86400, int(got))
+	}
+	if got := NetworkTime(86400).Time(); !got.Equal(ts) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", ts, got)
+	}
+	if got := NetworkTime(86400).String(); got != "2015-03-30T00:06:25Z" {
+		t.Fatalf("\nWanted: %v\n   Got: %v", "2015-03-30T00:06:25Z", got)
+	}
+	if got := NetworkTime(100).Add(90*time.Second + 500*time.Millisecond); got != 190 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 190, int(got))
+	}
+}
+
+func TestNetworkTime(t *testing.T) {
+	got, err := clientMock.NetworkTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 9232968 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 9232968, int(got))
+	}
+}
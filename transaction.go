@@ -15,8 +15,16 @@
 package nemgo
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
 )
 
 // TransactionMetadataPair is a set of metadata and transaction details
@@ -36,25 +44,54 @@ type TransactionMetadata struct {
 
 // Transaction contains information about a transaction
 type Transaction struct {
-	TimeStamp int
+	TimeStamp NetworkTime
 	Amount    int
 	Signature string
 	Fee       int
 	Recipient string
 	Type      int
-	Deadline  int
-	Message   message
+	Deadline  NetworkTime
+	Message   Message
 	Version   int
 	Signer    string
+	// Mosaics are the mosaics attached to a version 2 transfer
+	Mosaics []Mosaic
+	// OtherTrans is the inner transaction of a multisig transaction
+	OtherTrans *Transaction
+	// Signatures are the cosignatures of a multisig transaction
+	Signatures []Transaction
+	// OtherHash is the hash of the inner transaction a multisig
+	// signature cosigns
+	OtherHash hash
+	// OtherAccount is the address of the multisig account of a multisig
+	// signature
+	OtherAccount string
 }
 
 type hash struct {
 	Data string
 }
 
-type message struct {
-	Payload string
-	Type    int
+// TransactionHash computes the hash NIS assigns to a transaction.
+// The hash is the hex encoded SHA3-256 of the serialized transaction
+// data, without the signature, so it is known before the transaction is
+// announced and can be compared with TransactionMetadata.Hash.
+func TransactionHash(serialized []byte) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(serialized)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DecryptMessage will decrypt the encrypted message of a transaction
+// received by the owner of kp, using the signer's public key to derive
+// the shared key.
+// The sender of a transaction cannot use this as the recipient's public
+// key is not part of the transaction, use Message.Decrypt instead.
+func (t TransactionMetadataPair) DecryptMessage(kp crypto.KeyPair) (string, error) {
+	if strings.EqualFold(t.Transaction.Signer, kp.PublicKeyString()) {
+		return "", errors.New("key pair is the sender, use Message.Decrypt with the recipient public key")
+	}
+	return t.Transaction.Message.Decrypt(kp, PublicKey(t.Transaction.Signer))
 }
 
 // IncomingTransactions withh list all current pending transactions
@@ -118,23 +155,219 @@ func (c Client) AllTransactions(address string) ([]TransactionMetadataPair, erro
 	return data.Data, nil
 }
 
-// TODO make this work on a transaction object
-// // WithMosaic is an Option used for CreateTransaction
-// func WithMosaic(mosaic string, amt int) Option {
-// 	return func(c *Client) {}
-// }
+// TransactionByHash gets a confirmed transaction from its hash
+func (c Client) TransactionByHash(hash string) (TransactionMetadataPair, error) {
+	var data TransactionMetadataPair
+	c.url.Path = "/transaction/get"
+	req, err := c.buildReq(map[string]string{"hash": hash}, nil, http.MethodGet)
+	if err != nil {
+		return data, err
+	}
+	body, err := c.request(req)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
 
-// TODO put together the "sending transaction"
-// type SendTransaction struct {
-// 	Name   string
-// 	Amount string
-// }
+const (
+	// defaultDeadline is how long a transaction stays valid when no
+	// deadline is given
+	defaultDeadline = time.Hour
+	// maxDeadline is the longest a transaction can stay valid
+	maxDeadline = 24 * time.Hour
+	// mosaicMultiplier is the amount of a version 2 transfer, it makes
+	// the quantities of the attached mosaics the quantities transferred
+	mosaicMultiplier = 1000000
+	// maxMosaicQuantity is the largest quantity of any mosaic, in its
+	// smallest unit
+	maxMosaicQuantity = 9000000000000000
+)
 
-// CreateTransaction will initialize a transaction on the nem blockchain
-func (c Client) CreateTransaction(toAct string, xemAmt int, opts ...Option) (TransactionMetadataPair, error) {
-	var data TransactionMetadataPair
-	// for _, opt := range opts {
+type txOptions struct {
+	message  *Message
+	deadline time.Duration
+	fee      int64
+	feeSet   bool
+	mosaics  []Mosaic
+	warn     func(error)
+	err      error
+}
 
-	// }
-	return data, nil
+// TxOption can be passed into the transaction builders to change the
+// transaction being created
+type TxOption func(*txOptions)
+
+// newTxOptions applies opts over the defaults and validates the result
+func newTxOptions(opts []TxOption) (txOptions, error) {
+	o := txOptions{deadline: defaultDeadline}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.err != nil {
+		return o, o.err
+	}
+	if o.warn == nil {
+		o.warn = func(error) {}
+	}
+	if o.deadline < time.Second || o.deadline > maxDeadline {
+		return o, errors.Errorf("deadline must be between 1s and %v, got %v", maxDeadline, o.deadline)
+	}
+	return o, nil
+}
+
+// WithMessage attaches a message to a transfer
+func WithMessage(m Message) TxOption {
+	return func(o *txOptions) {
+		o.message = &m
+	}
+}
+
+// WithDeadline sets how long the transaction stays valid after it is
+// created, between a second and a day. The default is an hour.
+func WithDeadline(d time.Duration) TxOption {
+	return func(o *txOptions) {
+		o.deadline = d
+	}
+}
+
+// WithWarning sets the function called with anything a builder finds
+// worth pointing out that does not stop it from building the
+// transaction. By default warnings are ignored.
+func WithWarning(fn func(error)) TxOption {
+	return func(o *txOptions) {
+		o.warn = fn
+	}
+}
+
+// WithFee sets the fee, in micro XEM, instead of calculating it
+func WithFee(fee int64) TxOption {
+	return func(o *txOptions) {
+		o.fee = fee
+		o.feeSet = true
+	}
+}
+
+// setFee sets the fee of a transaction to the fee given with WithFee,
+// or to the fee calculated by Client.Fee
+func (c Client) setFee(tx TransactionEntity, o txOptions) error {
+	if o.feeSet {
+		tx.Common().Fee = o.fee
+		return nil
+	}
+	fee, err := c.Fee(tx)
+	if err != nil {
+		return err
+	}
+	tx.Common().Fee = fee
+	return nil
+}
+
+// WithMosaic attaches quantity of a mosaic, given as namespace:name, to
+// a transfer. The quantity is in the smallest unit of the mosaic, so
+// sending 1.5 of a mosaic with a divisibility of 2 takes a quantity of
+// 150. It can be used more than once to send several mosaics.
+func WithMosaic(mosaic string, quantity int64) TxOption {
+	return func(o *txOptions) {
+		id, err := ParseMosaicID(mosaic)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.mosaics = append(o.mosaics, Mosaic{MosaicID: id, Quantity: quantity})
+	}
+}
+
+// NewTransfer will build an unsigned transfer of xemAmt micro XEM from
+// signer to toAct. The transaction can be signed with SignTransaction
+// and sent with Announce.
+//
+// When mosaics are attached with WithMosaic a version 2 transfer is
+// built instead. Its amount is set to a multiplier of one XEM so the
+// quantities of the mosaics are the quantities transferred, and any
+// XEM is sent as a nem:xem mosaic. The fee of such a transfer depends
+// on the supply and divisibility of every mosaic, which are looked up
+// from NIS unless WithFee is used.
+func (c Client) NewTransfer(signer PublicKey, toAct string, xemAmt int64, opts ...TxOption) (TransferTransaction, error) {
+	var tx TransferTransaction
+	o, err := newTxOptions(opts)
+	if err != nil {
+		return tx, err
+	}
+	if err := signer.validate(c.network); err != nil {
+		return tx, errors.Wrap(err, "invalid signer")
+	}
+	if err := Address(toAct).validate(c.network); err != nil {
+		return tx, errors.Wrapf(err, "invalid recipient %q", toAct)
+	}
+	if xemAmt < 0 {
+		return tx, errors.New("amount must not be negative")
+	}
+	now, err := c.NetworkTime()
+	if err != nil {
+		return tx, errors.Wrap(err, "unable to get network time")
+	}
+	tx = TransferTransaction{
+		CommonTransaction: CommonTransaction{
+			Type:      TransferType,
+			Version:   version(c.network, 1),
+			TimeStamp: now,
+			Signer:    strings.ToLower(signer.String()),
+			Deadline:  now.Add(o.deadline)},
+		Recipient: toAct,
+		Amount:    xemAmt,
+		Message:   o.message}
+	if len(o.mosaics) > 0 {
+		mosaics, err := mergeMosaics(xemAmt, o.mosaics)
+		if err != nil {
+			return tx, err
+		}
+		tx.Version = version(c.network, 2)
+		tx.Amount = mosaicMultiplier
+		tx.Mosaics = mosaics
+	}
+	return tx, c.setFee(&tx, o)
+}
+
+// CreateTransaction will build a transfer of xemAmt micro XEM to toAct,
+// sign it with kp and announce it to NIS
+func (c Client) CreateTransaction(kp crypto.KeyPair, toAct string, xemAmt int, opts ...TxOption) (NemAnnounceResult, error) {
+	tx, err := c.NewTransfer(PublicKey(kp.PublicKeyString()), toAct, int64(xemAmt), opts...)
+	if err != nil {
+		return NemAnnounceResult{}, err
+	}
+	ra, err := SignTransaction(kp, &tx)
+	if err != nil {
+		return NemAnnounceResult{}, err
+	}
+	return c.Announce(ra)
+}
+
+// mergeMosaics adds the XEM sent to the mosaics, sums the quantities of
+// mosaics given more than once and sorts them by name as NIS expects
+func mergeMosaics(xemAmt int64, mosaics []Mosaic) ([]Mosaic, error) {
+	if xemAmt > 0 {
+		mosaics = append(mosaics, Mosaic{MosaicID: XEM, Quantity: xemAmt})
+	}
+	byID := make(map[MosaicID]int64)
+	for _, m := range mosaics {
+		if m.Quantity <= 0 {
+			return nil, errors.Errorf("quantity of %s must be positive", m.MosaicID)
+		}
+		byID[m.MosaicID] += m.Quantity
+		if byID[m.MosaicID] > maxMosaicQuantity {
+			return nil, errors.Errorf("quantity of %s is too large", m.MosaicID)
+		}
+	}
+	merged := make([]Mosaic, 0, len(byID))
+	for id, q := range byID {
+		merged = append(merged, Mosaic{MosaicID: id, Quantity: q})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].MosaicID.String() < merged[j].MosaicID.String()
+	})
+	return merged, nil
 }
@@ -15,8 +15,14 @@
 package nemgo
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
 )
 
 func TestIncomingTransactions(t *testing.T) {
@@ -37,7 +43,7 @@ func TestIncomingTransactions(t *testing.T) {
 				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
 				Type:      257,
 				Deadline:  9149600,
-				Message: message{
+				Message: Message{
 					Payload: "280000005444334b32493543524850595634425a5a5a4c335850454e4",
 					Type:    2,
 				},
@@ -61,7 +67,7 @@ func TestIncomingTransactions(t *testing.T) {
 				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
 				Type:      257,
 				Deadline:  9144741,
-				Message: message{
+				Message: Message{
 					Payload: "526f6262657279212121",
 					Type:    1,
 				},
@@ -97,7 +103,7 @@ func TestOutgoingTransactions(t *testing.T) {
 				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
 				Type:      257,
 				Deadline:  9149600,
-				Message: message{
+				Message: Message{
 					Payload: "280000005444334b32493543524850595634425a5a5a4c335850454e4",
 					Type:    2,
 				},
@@ -121,7 +127,7 @@ func TestOutgoingTransactions(t *testing.T) {
 				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
 				Type:      257,
 				Deadline:  9144741,
-				Message: message{
+				Message: Message{
 					Payload: "526f6262657279212121",
 					Type:    1,
 				},
@@ -139,6 +145,19 @@ func TestOutgoingTransactions(t *testing.T) {
 	}
 }
 
+func TestTransactionByHash(t *testing.T) {
+	got, err := clientMock.TransactionByHash("15c373ad4c3fe6af47d1941379ff262f785bdcfa07c02ac3608bc10da27d5e82")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Meta.Height != 40706 || got.Transaction.Amount != 1000000000 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 40706, got.Meta.Height)
+	}
+	if _, err := clientMock.TransactionByHash("00"); err == nil {
+		t.Fatal("expected an error for an unknown hash")
+	}
+}
+
 func TestAllTransactions(t *testing.T) {
 	want := []TransactionMetadataPair{
 		TransactionMetadataPair{
@@ -157,7 +176,7 @@ func TestAllTransactions(t *testing.T) {
 				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
 				Type:      257,
 				Deadline:  9149600,
-				Message: message{
+				Message: Message{
 					Payload: "280000005444334b32493543524850595634425a5a5a4c335850454e4",
 					Type:    2,
 				},
@@ -181,7 +200,7 @@ func TestAllTransactions(t *testing.T) {
 				Recipient: "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS",
 				Type:      257,
 				Deadline:  9144741,
-				Message: message{
+				Message: Message{
 					Payload: "526f6262657279212121",
 					Type:    1,
 				},
@@ -198,3 +217,200 @@ func TestAllTransactions(t *testing.T) {
 		t.Fatalf("\nWanted: %v\n Got: %v", want, got)
 	}
 }
+
+func TestDecryptMessage(t *testing.T) {
+	sender, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := crypto.FromHex("5b0e3fa5d3b49a79022d7c1e121ba1cbbf4db5821f47ab8c708ef88defc29bfe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := crypto.Encrypt(sender, recipient.Public, []byte("INV-2018-0042"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := TransactionMetadataPair{
+		Transaction: Transaction{
+			Signer: sender.PublicKeyString(),
+			Message: Message{
+				Payload: hex.EncodeToString(payload),
+				Type:    MessageTypeEncrypted}}}
+	got, err := tx.DecryptMessage(recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "INV-2018-0042" {
+		t.Fatalf("\nWanted: %v\n Got: %v", "INV-2018-0042", got)
+	}
+	if _, err := tx.DecryptMessage(sender); err == nil {
+		t.Fatal("expected an error decrypting as the sender")
+	}
+	tx.Transaction.Message.Type = MessageTypePlain
+	if _, err := tx.DecryptMessage(recipient); err == nil {
+		t.Fatal("expected an error decrypting a plain message")
+	}
+}
+
+func TestTransactionHash(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"616263", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	} {
+		data, err := hex.DecodeString(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := TransactionHash(data); got != tc.want {
+			t.Fatalf("\nWanted: %v\n Got: %v", tc.want, got)
+		}
+	}
+}
+
+func TestNewTransfer(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := PublicKey(kp.PublicKeyString())
+	tx, err := clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 150000000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type != TransferType || tx.Version != -1744830463 || tx.Signer != kp.PublicKeyString() {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if tx.TimeStamp != 9232968 || tx.Deadline-tx.TimeStamp != 3600 {
+		t.Fatalf("expected a one hour deadline from the network time, got %d to %d", tx.TimeStamp, tx.Deadline)
+	}
+	// 150,000 XEM costs 15 fee units
+	if tx.Fee != 750000 {
+		t.Fatalf("\nWanted: %v\n Got: %v", 750000, tx.Fee)
+	}
+	tx, err = clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000, WithMessage(NewPlainMessage("Robbery!!!")), WithFee(1234567))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Fee != 1234567 || tx.Message == nil {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	tx, err = clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000, WithDeadline(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Deadline-tx.TimeStamp != 5400 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 5400, tx.Deadline-tx.TimeStamp)
+	}
+	for _, d := range []time.Duration{0, 25 * time.Hour} {
+		if _, err := clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000, WithDeadline(d)); err == nil {
+			t.Fatalf("expected an error for a deadline of %v", d)
+		}
+	}
+	tx, err = clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000, WithFee(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Fee != 0 {
+		t.Fatalf("expected the fee set with WithFee to be kept, got %v", tx.Fee)
+	}
+	for _, to := range []string{"TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TO", "NBMBTUB6JIXGBSETDJBMCGLB2GPTI6GMPAYFNH3P"} {
+		if _, err := clientMock.NewTransfer(signer, to, 1000000); err == nil {
+			t.Fatalf("expected an error for recipient %v", to)
+		}
+	}
+	if _, err := clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", -1); err == nil {
+		t.Fatal("expected an error for a negative amount")
+	}
+}
+
+func TestCreateTransaction(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var announced RequestAnnounce
+	c := Client{
+		network: Testnet,
+		request: func(req *http.Request) ([]byte, error) {
+			if req.URL.Path != "/transaction/announce" {
+				return sendReqMock(req)
+			}
+			if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+				t.Fatalf("unexpected request %v %v", req.Method, req.Header)
+			}
+			if err := json.NewDecoder(req.Body).Decode(&announced); err != nil {
+				t.Fatal(err)
+			}
+			return sendReqMock(req)
+		}}
+	got, err := c.CreateTransaction(kp, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != 1 || got.Message != "SUCCESS" || got.TransactionHash.Data != "c1786437336da077cd572a27710c40c378610e8d33880bcb7bdb0a42e3d35586" {
+		t.Fatalf("unexpected result %+v", got)
+	}
+	data, err := hex.DecodeString(announced.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := hex.DecodeString(announced.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crypto.Verify(kp.Public, data, sig) {
+		t.Fatal("expected the announced transaction to be signed by the key pair")
+	}
+}
+
+func TestSignTransactionWrongSigner(t *testing.T) {
+	kp, err := crypto.NewKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &TransferTransaction{CommonTransaction: CommonTransaction{Signer: testSigner}}
+	if _, err := SignTransaction(kp, tx); err == nil {
+		t.Fatal("expected an error signing with another key pair")
+	}
+}
+
+func TestNewMosaicTransfer(t *testing.T) {
+	kp, err := crypto.FromHex("575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := PublicKey(kp.PublicKeyString())
+	tx, err := clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 150000000000,
+		WithMosaic("alice.drinks:tokens", 20000),
+		WithMosaic("alice.drinks:orange juice", 1000),
+		WithMosaic("alice.drinks:tokens", 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxVersion() != 2 || tx.Amount != 1000000 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	want := []Mosaic{
+		{MosaicID: MosaicID{NamespaceID: "alice.drinks", Name: "orange juice"}, Quantity: 1000},
+		{MosaicID: MosaicID{NamespaceID: "alice.drinks", Name: "tokens"}, Quantity: 20005},
+		{MosaicID: XEM, Quantity: 150000000000}}
+	if !reflect.DeepEqual(want, tx.Mosaics) {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, tx.Mosaics)
+	}
+	// 1 unit for the juice, 4 for the tokens and 15 for the XEM
+	if tx.Fee != 1000000 {
+		t.Fatalf("\nWanted: %v\n   Got: %v", 1000000, tx.Fee)
+	}
+	if _, err := Serialize(&tx); err != nil {
+		t.Fatal(err)
+	}
+	for _, opt := range []TxOption{WithMosaic("tokens", 1), WithMosaic("alice.drinks:tokens", 0)} {
+		if _, err := clientMock.NewTransfer(signer, "TALICELCD3XPH4FFI5STGGNSNSWPOTG5E4DS2TOS", 0, opt); err == nil {
+			t.Fatal("expected an error for an invalid mosaic")
+		}
+	}
+}
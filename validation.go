@@ -0,0 +1,266 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+// ValidationResult is the outcome of NIS validating a transaction, the
+// Code and Message of a NemAnnounceResult. A ValidationResult other than
+// ValidationNeutral and ValidationSuccess is a failure and can be used
+// as an error.
+type ValidationResult int
+
+// The NIS1 validation results, named after their message
+const (
+	ValidationNeutral ValidationResult = iota
+	ValidationSuccess
+	FailureUnknown
+	FailureFutureDeadline
+	FailurePastDeadline
+	FailureInsufficientBalance
+	FailureMessageTooLarge
+	FailureHashExists
+	FailureSignatureNotVerifiable
+	FailureTimestampTooFarInPast
+	FailureTimestampTooFarInFuture
+	FailureEntityUnusableOutOfSync
+	FailureChainScoreInferior
+	FailureChainInvalid
+	FailureConflictingImportanceTransfer
+	FailureTooManyTransactions
+	FailureSelfSignedTransaction
+	FailureInsufficientFee
+	FailureEntityInvalidVersion
+	FailureWrongNetwork
+	FailureTransactionCacheTooFull
+	FailureIneligibleBlockSigner
+	FailureHistoricalImportancesUnavailable
+	FailureMaxChainSizeExceeded
+	FailureImportanceTransferInProgress
+	FailureImportanceTransferNeedsToBeDeactivated
+	FailureDestinationAccountHasNonzeroBalance
+	FailureTransactionNotAllowedForRemote
+	FailureMultisigNotACosigner
+	FailureMultisigInvalidCosigners
+	FailureMultisigNoMatchingMultisig
+	FailureMultisigMismatchedSignature
+	FailureMultisigAlreadyACosigner
+	FailureMultisigAccountCannotBeCosigner
+	FailureMultisigModificationMultipleDeletes
+	FailureMultisigModificationRedundantModifications
+	FailureMultisigMinCosignatoriesOutOfRange
+	FailureTooManyMultisigCosigners
+	FailureTransactionNotAllowedForMultisig
+	FailureNamespaceUnknown
+	FailureNamespaceAlreadyExists
+	FailureNamespaceExpired
+	FailureNamespaceOwnerConflict
+	FailureNamespaceInvalidName
+	FailureNamespaceInvalidRentalFeeSink
+	FailureNamespaceInvalidRentalFee
+	FailureNamespaceProvisionTooEarly
+	FailureNamespaceNotClaimable
+	FailureMosaicCreatorConflict
+	FailureMosaicUnknown
+	FailureMosaicModificationNotAllowed
+	FailureMosaicInvalidCreationFeeSink
+	FailureMosaicInvalidCreationFee
+	FailureMosaicMaxSupplyExceeded
+	FailureMosaicSupplyNegative
+	FailureMosaicSupplyImmutable
+	FailureMosaicDivisibilityViolation
+	FailureMosaicNotTransferable
+	FailureMosaicLevyNotTransferable
+	FailureTooManyMosaicTransfers
+	FailureNemesisAccountTransactionAfterNemesisBlock
+)
+
+// Retry is what to do with a transaction NIS rejected
+type Retry int
+
+const (
+	// NoRetry means announcing the transaction again, even rebuilt,
+	// would fail the same way and someone needs to look at it
+	NoRetry Retry = iota
+	// RetryLater means the same signed transaction can be announced
+	// again later, as long as its deadline has not passed
+	RetryLater
+	// RetryRebuild means a new transaction has to be built, signed and
+	// announced, with a fresh timestamp and deadline or a higher fee
+	RetryRebuild
+)
+
+func (r Retry) String() string {
+	switch r {
+	case NoRetry:
+		return "no retry"
+	case RetryLater:
+		return "retry later"
+	case RetryRebuild:
+		return "rebuild"
+	default:
+		return "unknown"
+	}
+}
+
+// noCode marks validation results whose numeric code is not mapped,
+// they are only recognized by their message
+const noCode = -1
+
+var validationResults = [...]struct {
+	message string
+	code    int
+	retry   Retry
+}{
+	ValidationNeutral:                                 {"NEUTRAL", 0, NoRetry},
+	ValidationSuccess:                                 {"SUCCESS", 1, NoRetry},
+	FailureUnknown:                                    {"FAILURE_UNKNOWN", 2, NoRetry},
+	FailureFutureDeadline:                             {"FAILURE_FUTURE_DEADLINE", 3, RetryRebuild},
+	FailurePastDeadline:                               {"FAILURE_PAST_DEADLINE", 4, RetryRebuild},
+	FailureInsufficientBalance:                        {"FAILURE_INSUFFICIENT_BALANCE", 5, RetryLater},
+	FailureMessageTooLarge:                            {"FAILURE_MESSAGE_TOO_LARGE", 6, NoRetry},
+	FailureHashExists:                                 {"FAILURE_HASH_EXISTS", 7, NoRetry},
+	FailureSignatureNotVerifiable:                     {"FAILURE_SIGNATURE_NOT_VERIFIABLE", 8, NoRetry},
+	FailureTimestampTooFarInPast:                      {"FAILURE_TIMESTAMP_TOO_FAR_IN_PAST", 9, RetryRebuild},
+	FailureTimestampTooFarInFuture:                    {"FAILURE_TIMESTAMP_TOO_FAR_IN_FUTURE", 10, RetryRebuild},
+	FailureEntityUnusableOutOfSync:                    {"FAILURE_ENTITY_UNUSABLE_OUT_OF_SYNC", 11, RetryLater},
+	FailureChainScoreInferior:                         {"FAILURE_CHAIN_SCORE_INFERIOR", 12, NoRetry},
+	FailureChainInvalid:                               {"FAILURE_CHAIN_INVALID", 13, NoRetry},
+	FailureConflictingImportanceTransfer:              {"FAILURE_CONFLICTING_IMPORTANCE_TRANSFER", 14, RetryLater},
+	FailureTooManyTransactions:                        {"FAILURE_TOO_MANY_TRANSACTIONS", 15, RetryLater},
+	FailureSelfSignedTransaction:                      {"FAILURE_SELF_SIGNED_TRANSACTION", 16, NoRetry},
+	FailureInsufficientFee:                            {"FAILURE_INSUFFICIENT_FEE", 17, RetryRebuild},
+	FailureEntityInvalidVersion:                       {"FAILURE_ENTITY_INVALID_VERSION", noCode, NoRetry},
+	FailureWrongNetwork:                               {"FAILURE_WRONG_NETWORK", noCode, NoRetry},
+	FailureTransactionCacheTooFull:                    {"FAILURE_TRANSACTION_CACHE_TOO_FULL", noCode, RetryLater},
+	FailureIneligibleBlockSigner:                      {"FAILURE_INELIGIBLE_BLOCK_SIGNER", noCode, NoRetry},
+	FailureHistoricalImportancesUnavailable:           {"FAILURE_HISTORICAL_IMPORTANCES_UNAVAILABLE", noCode, RetryLater},
+	FailureMaxChainSizeExceeded:                       {"FAILURE_MAX_CHAIN_SIZE_EXCEEDED", noCode, NoRetry},
+	FailureImportanceTransferInProgress:               {"FAILURE_IMPORTANCE_TRANSFER_IN_PROGRESS", noCode, RetryLater},
+	FailureImportanceTransferNeedsToBeDeactivated:     {"FAILURE_IMPORTANCE_TRANSFER_NEEDS_TO_BE_DEACTIVATED", noCode, NoRetry},
+	FailureDestinationAccountHasNonzeroBalance:        {"FAILURE_DESTINATION_ACCOUNT_HAS_NONZERO_BALANCE", noCode, NoRetry},
+	FailureTransactionNotAllowedForRemote:             {"FAILURE_TRANSACTION_NOT_ALLOWED_FOR_REMOTE", noCode, NoRetry},
+	FailureMultisigNotACosigner:                       {"FAILURE_MULTISIG_NOT_A_COSIGNER", noCode, NoRetry},
+	FailureMultisigInvalidCosigners:                   {"FAILURE_MULTISIG_INVALID_COSIGNERS", noCode, NoRetry},
+	FailureMultisigNoMatchingMultisig:                 {"FAILURE_MULTISIG_NO_MATCHING_MULTISIG", noCode, RetryLater},
+	FailureMultisigMismatchedSignature:                {"FAILURE_MULTISIG_MISMATCHED_SIGNATURE", noCode, NoRetry},
+	FailureMultisigAlreadyACosigner:                   {"FAILURE_MULTISIG_ALREADY_A_COSIGNER", noCode, NoRetry},
+	FailureMultisigAccountCannotBeCosigner:            {"FAILURE_MULTISIG_ACCOUNT_CANNOT_BE_COSIGNER", noCode, NoRetry},
+	FailureMultisigModificationMultipleDeletes:        {"FAILURE_MULTISIG_MODIFICATION_MULTIPLE_DELETES", noCode, NoRetry},
+	FailureMultisigModificationRedundantModifications: {"FAILURE_MULTISIG_MODIFICATION_REDUNDANT_MODIFICATIONS", noCode, NoRetry},
+	FailureMultisigMinCosignatoriesOutOfRange:         {"FAILURE_MULTISIG_MIN_COSIGNATORIES_OUT_OF_RANGE", noCode, NoRetry},
+	FailureTooManyMultisigCosigners:                   {"FAILURE_TOO_MANY_MULTISIG_COSIGNERS", noCode, NoRetry},
+	FailureTransactionNotAllowedForMultisig:           {"FAILURE_TRANSACTION_NOT_ALLOWED_FOR_MULTISIG", noCode, NoRetry},
+	FailureNamespaceUnknown:                           {"FAILURE_NAMESPACE_UNKNOWN", noCode, NoRetry},
+	FailureNamespaceAlreadyExists:                     {"FAILURE_NAMESPACE_ALREADY_EXISTS", noCode, NoRetry},
+	FailureNamespaceExpired:                           {"FAILURE_NAMESPACE_EXPIRED", noCode, NoRetry},
+	FailureNamespaceOwnerConflict:                     {"FAILURE_NAMESPACE_OWNER_CONFLICT", noCode, NoRetry},
+	FailureNamespaceInvalidName:                       {"FAILURE_NAMESPACE_INVALID_NAME", noCode, NoRetry},
+	FailureNamespaceInvalidRentalFeeSink:              {"FAILURE_NAMESPACE_INVALID_RENTAL_FEE_SINK", noCode, NoRetry},
+	FailureNamespaceInvalidRentalFee:                  {"FAILURE_NAMESPACE_INVALID_RENTAL_FEE", noCode, RetryRebuild},
+	FailureNamespaceProvisionTooEarly:                 {"FAILURE_NAMESPACE_PROVISION_TOO_EARLY", noCode, RetryLater},
+	FailureNamespaceNotClaimable:                      {"FAILURE_NAMESPACE_NOT_CLAIMABLE", noCode, NoRetry},
+	FailureMosaicCreatorConflict:                      {"FAILURE_MOSAIC_CREATOR_CONFLICT", noCode, NoRetry},
+	FailureMosaicUnknown:                              {"FAILURE_MOSAIC_UNKNOWN", noCode, NoRetry},
+	FailureMosaicModificationNotAllowed:               {"FAILURE_MOSAIC_MODIFICATION_NOT_ALLOWED", noCode, NoRetry},
+	FailureMosaicInvalidCreationFeeSink:               {"FAILURE_MOSAIC_INVALID_CREATION_FEE_SINK", noCode, NoRetry},
+	FailureMosaicInvalidCreationFee:                   {"FAILURE_MOSAIC_INVALID_CREATION_FEE", noCode, RetryRebuild},
+	FailureMosaicMaxSupplyExceeded:                    {"FAILURE_MOSAIC_MAX_SUPPLY_EXCEEDED", noCode, NoRetry},
+	FailureMosaicSupplyNegative:                       {"FAILURE_MOSAIC_SUPPLY_NEGATIVE", noCode, NoRetry},
+	FailureMosaicSupplyImmutable:                      {"FAILURE_MOSAIC_SUPPLY_IMMUTABLE", noCode, NoRetry},
+	FailureMosaicDivisibilityViolation:                {"FAILURE_MOSAIC_DIVISIBILITY_VIOLATION", noCode, NoRetry},
+	FailureMosaicNotTransferable:                      {"FAILURE_MOSAIC_NOT_TRANSFERABLE", noCode, NoRetry},
+	FailureMosaicLevyNotTransferable:                  {"FAILURE_MOSAIC_LEVY_NOT_TRANSFERABLE", noCode, NoRetry},
+	FailureTooManyMosaicTransfers:                     {"FAILURE_TOO_MANY_MOSAIC_TRANSFERS", noCode, NoRetry},
+	FailureNemesisAccountTransactionAfterNemesisBlock: {"FAILURE_NEMESIS_ACCOUNT_TRANSACTION_AFTER_NEMESIS_BLOCK", noCode, NoRetry},
+}
+
+// ParseValidationResult finds the validation result with the given
+// message, such as "FAILURE_INSUFFICIENT_BALANCE"
+func ParseValidationResult(message string) (ValidationResult, bool) {
+	for v, r := range validationResults {
+		if r.message == message {
+			return ValidationResult(v), true
+		}
+	}
+	return FailureUnknown, false
+}
+
+// ValidationResultOfCode finds the validation result with the given
+// numeric code. Only the general results, up to FailureInsufficientFee,
+// have a code mapped, the others are recognized by their message.
+func ValidationResultOfCode(code int) (ValidationResult, bool) {
+	for v, r := range validationResults {
+		if r.code != noCode && r.code == code {
+			return ValidationResult(v), true
+		}
+	}
+	return FailureUnknown, false
+}
+
+func (v ValidationResult) valid() bool {
+	return v >= 0 && int(v) < len(validationResults)
+}
+
+// String returns the message NIS uses for the validation result
+func (v ValidationResult) String() string {
+	if !v.valid() {
+		return validationResults[FailureUnknown].message
+	}
+	return validationResults[v].message
+}
+
+// Error makes failed validation results usable as errors
+func (v ValidationResult) Error() string {
+	return "transaction rejected by NIS: " + v.String()
+}
+
+// Code returns the numeric code NIS uses for the validation result, if
+// it is mapped
+func (v ValidationResult) Code() (int, bool) {
+	if !v.valid() || validationResults[v].code == noCode {
+		return 0, false
+	}
+	return validationResults[v].code, true
+}
+
+// Failed reports whether the validation result is a failure
+func (v ValidationResult) Failed() bool {
+	return v != ValidationNeutral && v != ValidationSuccess
+}
+
+// Retry tells what to do with a transaction that failed with the
+// validation result
+func (v ValidationResult) Retry() Retry {
+	if !v.valid() {
+		return NoRetry
+	}
+	return validationResults[v].retry
+}
+
+// Retryable reports whether the transaction, as is or rebuilt, may
+// succeed if announced again
+func (v ValidationResult) Retryable() bool {
+	return v.Retry() != NoRetry
+}
+
+// Result decodes the validation result of an announce from its message,
+// or from its code when the message is not known. FailureUnknown is
+// returned when neither is.
+func (r NemAnnounceResult) Result() ValidationResult {
+	if v, ok := ParseValidationResult(r.Message); ok {
+		return v
+	}
+	v, _ := ValidationResultOfCode(r.Code)
+	return v
+}
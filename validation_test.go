@@ -0,0 +1,178 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nemgo
+
+import "testing"
+
+func TestValidationResultTable(t *testing.T) {
+	// code is -1 for results only recognized by their message
+	tests := []struct {
+		v       ValidationResult
+		message string
+		code    int
+	}{
+		{ValidationNeutral, "NEUTRAL", 0},
+		{ValidationSuccess, "SUCCESS", 1},
+		{FailureUnknown, "FAILURE_UNKNOWN", 2},
+		{FailureFutureDeadline, "FAILURE_FUTURE_DEADLINE", 3},
+		{FailurePastDeadline, "FAILURE_PAST_DEADLINE", 4},
+		{FailureInsufficientBalance, "FAILURE_INSUFFICIENT_BALANCE", 5},
+		{FailureMessageTooLarge, "FAILURE_MESSAGE_TOO_LARGE", 6},
+		{FailureHashExists, "FAILURE_HASH_EXISTS", 7},
+		{FailureSignatureNotVerifiable, "FAILURE_SIGNATURE_NOT_VERIFIABLE", 8},
+		{FailureTimestampTooFarInPast, "FAILURE_TIMESTAMP_TOO_FAR_IN_PAST", 9},
+		{FailureTimestampTooFarInFuture, "FAILURE_TIMESTAMP_TOO_FAR_IN_FUTURE", 10},
+		{FailureEntityUnusableOutOfSync, "FAILURE_ENTITY_UNUSABLE_OUT_OF_SYNC", 11},
+		{FailureChainScoreInferior, "FAILURE_CHAIN_SCORE_INFERIOR", 12},
+		{FailureChainInvalid, "FAILURE_CHAIN_INVALID", 13},
+		{FailureConflictingImportanceTransfer, "FAILURE_CONFLICTING_IMPORTANCE_TRANSFER", 14},
+		{FailureTooManyTransactions, "FAILURE_TOO_MANY_TRANSACTIONS", 15},
+		{FailureSelfSignedTransaction, "FAILURE_SELF_SIGNED_TRANSACTION", 16},
+		{FailureInsufficientFee, "FAILURE_INSUFFICIENT_FEE", 17},
+		{FailureEntityInvalidVersion, "FAILURE_ENTITY_INVALID_VERSION", -1},
+		{FailureWrongNetwork, "FAILURE_WRONG_NETWORK", -1},
+		{FailureTransactionCacheTooFull, "FAILURE_TRANSACTION_CACHE_TOO_FULL", -1},
+		{FailureIneligibleBlockSigner, "FAILURE_INELIGIBLE_BLOCK_SIGNER", -1},
+		{FailureHistoricalImportancesUnavailable, "FAILURE_HISTORICAL_IMPORTANCES_UNAVAILABLE", -1},
+		{FailureMaxChainSizeExceeded, "FAILURE_MAX_CHAIN_SIZE_EXCEEDED", -1},
+		{FailureImportanceTransferInProgress, "FAILURE_IMPORTANCE_TRANSFER_IN_PROGRESS", -1},
+		{FailureImportanceTransferNeedsToBeDeactivated, "FAILURE_IMPORTANCE_TRANSFER_NEEDS_TO_BE_DEACTIVATED", -1},
+		{FailureDestinationAccountHasNonzeroBalance, "FAILURE_DESTINATION_ACCOUNT_HAS_NONZERO_BALANCE", -1},
+		{FailureTransactionNotAllowedForRemote, "FAILURE_TRANSACTION_NOT_ALLOWED_FOR_REMOTE", -1},
+		{FailureMultisigNotACosigner, "FAILURE_MULTISIG_NOT_A_COSIGNER", -1},
+		{FailureMultisigInvalidCosigners, "FAILURE_MULTISIG_INVALID_COSIGNERS", -1},
+		{FailureMultisigNoMatchingMultisig, "FAILURE_MULTISIG_NO_MATCHING_MULTISIG", -1},
+		{FailureMultisigMismatchedSignature, "FAILURE_MULTISIG_MISMATCHED_SIGNATURE", -1},
+		{FailureMultisigAlreadyACosigner, "FAILURE_MULTISIG_ALREADY_A_COSIGNER", -1},
+		{FailureMultisigAccountCannotBeCosigner, "FAILURE_MULTISIG_ACCOUNT_CANNOT_BE_COSIGNER", -1},
+		{FailureMultisigModificationMultipleDeletes, "FAILURE_MULTISIG_MODIFICATION_MULTIPLE_DELETES", -1},
+		{FailureMultisigModificationRedundantModifications, "FAILURE_MULTISIG_MODIFICATION_REDUNDANT_MODIFICATIONS", -1},
+		{FailureMultisigMinCosignatoriesOutOfRange, "FAILURE_MULTISIG_MIN_COSIGNATORIES_OUT_OF_RANGE", -1},
+		{FailureTooManyMultisigCosigners, "FAILURE_TOO_MANY_MULTISIG_COSIGNERS", -1},
+		{FailureTransactionNotAllowedForMultisig, "FAILURE_TRANSACTION_NOT_ALLOWED_FOR_MULTISIG", -1},
+		{FailureNamespaceUnknown, "FAILURE_NAMESPACE_UNKNOWN", -1},
+		{FailureNamespaceAlreadyExists, "FAILURE_NAMESPACE_ALREADY_EXISTS", -1},
+		{FailureNamespaceExpired, "FAILURE_NAMESPACE_EXPIRED", -1},
+		{FailureNamespaceOwnerConflict, "FAILURE_NAMESPACE_OWNER_CONFLICT", -1},
+		{FailureNamespaceInvalidName, "FAILURE_NAMESPACE_INVALID_NAME", -1},
+		{FailureNamespaceInvalidRentalFeeSink, "FAILURE_NAMESPACE_INVALID_RENTAL_FEE_SINK", -1},
+		{FailureNamespaceInvalidRentalFee, "FAILURE_NAMESPACE_INVALID_RENTAL_FEE", -1},
+		{FailureNamespaceProvisionTooEarly, "FAILURE_NAMESPACE_PROVISION_TOO_EARLY", -1},
+		{FailureNamespaceNotClaimable, "FAILURE_NAMESPACE_NOT_CLAIMABLE", -1},
+		{FailureMosaicCreatorConflict, "FAILURE_MOSAIC_CREATOR_CONFLICT", -1},
+		{FailureMosaicUnknown, "FAILURE_MOSAIC_UNKNOWN", -1},
+		{FailureMosaicModificationNotAllowed, "FAILURE_MOSAIC_MODIFICATION_NOT_ALLOWED", -1},
+		{FailureMosaicInvalidCreationFeeSink, "FAILURE_MOSAIC_INVALID_CREATION_FEE_SINK", -1},
+		{FailureMosaicInvalidCreationFee, "FAILURE_MOSAIC_INVALID_CREATION_FEE", -1},
+		{FailureMosaicMaxSupplyExceeded, "FAILURE_MOSAIC_MAX_SUPPLY_EXCEEDED", -1},
+		{FailureMosaicSupplyNegative, "FAILURE_MOSAIC_SUPPLY_NEGATIVE", -1},
+		{FailureMosaicSupplyImmutable, "FAILURE_MOSAIC_SUPPLY_IMMUTABLE", -1},
+		{FailureMosaicDivisibilityViolation, "FAILURE_MOSAIC_DIVISIBILITY_VIOLATION", -1},
+		{FailureMosaicNotTransferable, "FAILURE_MOSAIC_NOT_TRANSFERABLE", -1},
+		{FailureMosaicLevyNotTransferable, "FAILURE_MOSAIC_LEVY_NOT_TRANSFERABLE", -1},
+		{FailureTooManyMosaicTransfers, "FAILURE_TOO_MANY_MOSAIC_TRANSFERS", -1},
+		{FailureNemesisAccountTransactionAfterNemesisBlock, "FAILURE_NEMESIS_ACCOUNT_TRANSACTION_AFTER_NEMESIS_BLOCK", -1},
+	}
+	if len(tests) != len(validationResults) {
+		t.Fatalf("\nWanted: %v results\n   Got: %v", len(validationResults), len(tests))
+	}
+	for _, tt := range tests {
+		if got, ok := ParseValidationResult(tt.message); !ok || got != tt.v {
+			t.Fatalf("%s\nWanted: %v\n   Got: %v", tt.message, tt.v.String(), got.String())
+		}
+		code, ok := tt.v.Code()
+		if tt.code < 0 {
+			if ok {
+				t.Fatalf("%s: unexpected code %d", tt.message, code)
+			}
+			continue
+		}
+		if !ok || code != tt.code {
+			t.Fatalf("%s\nWanted: %v\n   Got: %v", tt.message, tt.code, code)
+		}
+		if got, ok := ValidationResultOfCode(tt.code); !ok || got != tt.v {
+			t.Fatalf("%d\nWanted: %v\n   Got: %v", tt.code, tt.v.String(), got.String())
+		}
+	}
+}
+
+func TestParseValidationResult(t *testing.T) {
+	for v := ValidationNeutral; int(v) < len(validationResults); v++ {
+		got, ok := ParseValidationResult(v.String())
+		if !ok || got != v {
+			t.Fatalf("\nWanted: %v\n   Got: %v", v.String(), got.String())
+		}
+		if code, ok := v.Code(); ok {
+			if got, _ := ValidationResultOfCode(code); got != v {
+				t.Fatalf("\nWanted: %v\n   Got: %v", v.String(), got.String())
+			}
+		}
+	}
+	if _, ok := ParseValidationResult("FAILURE_SOMETHING_NEW"); ok {
+		t.Fatal("expected an unknown message not to be found")
+	}
+	if _, ok := ValidationResultOfCode(9999); ok {
+		t.Fatal("expected an unknown code not to be found")
+	}
+}
+
+func TestValidationResultRetry(t *testing.T) {
+	tests := []struct {
+		v     ValidationResult
+		retry Retry
+	}{
+		{ValidationSuccess, NoRetry},
+		{FailurePastDeadline, RetryRebuild},
+		{FailureInsufficientFee, RetryRebuild},
+		{FailureInsufficientBalance, RetryLater},
+		{FailureTransactionCacheTooFull, RetryLater},
+		{FailureSignatureNotVerifiable, NoRetry},
+		{FailureMultisigNotACosigner, NoRetry},
+		{ValidationResult(1000), NoRetry},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Retry(); got != tt.retry {
+			t.Fatalf("%v\nWanted: %v\n   Got: %v", tt.v.String(), tt.retry, got)
+		}
+		if tt.v.Retryable() != (tt.retry != NoRetry) {
+			t.Fatalf("%v: unexpected Retryable", tt.v.String())
+		}
+	}
+	if ValidationSuccess.Failed() || ValidationNeutral.Failed() || !FailureHashExists.Failed() {
+		t.Fatal("unexpected Failed")
+	}
+}
+
+func TestNemAnnounceResultResult(t *testing.T) {
+	tests := []struct {
+		r    NemAnnounceResult
+		want ValidationResult
+	}{
+		{NemAnnounceResult{Type: 1, Code: 1, Message: "SUCCESS"}, ValidationSuccess},
+		{NemAnnounceResult{Type: 1, Code: 5, Message: "FAILURE_INSUFFICIENT_BALANCE"}, FailureInsufficientBalance},
+		{NemAnnounceResult{Type: 1, Code: 4}, FailurePastDeadline},
+		{NemAnnounceResult{Type: 1, Code: 9999, Message: "FAILURE_MOSAIC_NOT_TRANSFERABLE"}, FailureMosaicNotTransferable},
+		{NemAnnounceResult{Type: 1, Code: 9999, Message: "FAILURE_SOMETHING_NEW"}, FailureUnknown},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Result(); got != tt.want {
+			t.Fatalf("\nWanted: %v\n   Got: %v", tt.want.String(), got.String())
+		}
+	}
+	var err error = FailurePastDeadline
+	if want := "transaction rejected by NIS: FAILURE_PAST_DEADLINE"; err.Error() != want {
+		t.Fatalf("\nWanted: %v\n   Got: %v", want, err)
+	}
+}
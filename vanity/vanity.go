@@ -0,0 +1,295 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vanity searches for key pairs whose NEM address matches a
+// pattern, such as a branded prefix.
+package vanity
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+)
+
+const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// Mode is where in an address a pattern must appear
+type Mode int
+
+const (
+	// Prefix matches addresses starting with the pattern, including the
+	// network letter
+	Prefix Mode = iota
+	// Suffix matches addresses ending with the pattern
+	Suffix
+	// Contains matches addresses holding the pattern anywhere after the
+	// network letter
+	Contains
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Prefix:
+		return "prefix"
+	case Suffix:
+		return "suffix"
+	case Contains:
+		return "contains"
+	default:
+		return "unknown"
+	}
+}
+
+// Pattern is a validated vanity pattern for a network
+type Pattern struct {
+	Text    string
+	Mode    Mode
+	Network byte
+}
+
+// NewPattern validates text against the base32 alphabet used by
+// addresses and returns a pattern for the network.
+// Lowercase letters are accepted and converted to uppercase. A prefix
+// must start with the letters every address on the network starts
+// with, which means mainnet addresses start with one of NA, NB, NC or ND.
+func NewPattern(text string, mode Mode, network byte) (Pattern, error) {
+	text = strings.ToUpper(text)
+	p := Pattern{Text: text, Mode: mode, Network: network}
+	if text == "" {
+		return p, errors.New("pattern must not be empty")
+	}
+	if len(text) > crypto.AddressLength {
+		return p, errors.Errorf("pattern must be at most %d characters", crypto.AddressLength)
+	}
+	for i, r := range text {
+		if !strings.ContainsRune(alphabet, r) {
+			return p, errors.Errorf("pattern contains %q at position %d, addresses only use A-Z and 2-7", r, i)
+		}
+	}
+	switch mode {
+	case Prefix:
+		first, second := networkChars(network)
+		if text[0] != first {
+			return p, errors.Errorf("addresses on network 0x%x always start with %c", network, first)
+		}
+		if len(text) > 1 && !strings.ContainsRune(second, rune(text[1])) {
+			return p, errors.Errorf("the second character of addresses on network 0x%x is one of %s", network, second)
+		}
+	case Contains:
+		if len(text) > crypto.AddressLength-1 {
+			return p, errors.Errorf("pattern must be at most %d characters", crypto.AddressLength-1)
+		}
+	case Suffix:
+	default:
+		return p, errors.Errorf("unknown pattern mode %d", mode)
+	}
+	return p, nil
+}
+
+// Match reports whether address matches the pattern
+func (p Pattern) Match(address string) bool {
+	switch p.Mode {
+	case Prefix:
+		return strings.HasPrefix(address, p.Text)
+	case Suffix:
+		return strings.HasSuffix(address, p.Text)
+	case Contains:
+		// the network letter is the same for every address
+		return len(address) > 0 && strings.Contains(address[1:], p.Text)
+	}
+	return false
+}
+
+// ExpectedAttempts estimates the number of key pairs that have to be
+// generated on average before one matches the pattern.
+// The estimate for Contains assumes matches at different positions are
+// independent and is approximate.
+func (p Pattern) ExpectedAttempts() float64 {
+	n := len(p.Text)
+	switch p.Mode {
+	case Prefix:
+		// the first character is fixed by the network and the second
+		// has 4 possible values
+		if n <= 1 {
+			return 1
+		}
+		return 4 * math.Pow(32, float64(n-2))
+	case Suffix:
+		return math.Pow(32, float64(n))
+	case Contains:
+		// positions after the fixed network letter
+		positions := crypto.AddressLength - 1 - n + 1
+		if positions < 1 {
+			positions = 1
+		}
+		return math.Pow(32, float64(n)) / float64(positions)
+	}
+	return math.Inf(1)
+}
+
+// networkChars returns the letter every address on the network starts
+// with and the letters that can follow it
+func networkChars(network byte) (byte, string) {
+	first := alphabet[network>>3]
+	base := int(network&0x07) << 2
+	return first, alphabet[base : base+4]
+}
+
+// Result is a key pair found by Search
+type Result struct {
+	KeyPair  crypto.KeyPair
+	Address  string
+	Attempts uint64
+	Elapsed  time.Duration
+}
+
+// Progress is reported periodically while searching
+type Progress struct {
+	Attempts uint64
+	Elapsed  time.Duration
+	// Rate is the number of attempts per second
+	Rate float64
+	// Expected is the expected number of attempts for the pattern
+	Expected float64
+}
+
+type searcher struct {
+	workers  int
+	progress func(Progress)
+	interval time.Duration
+}
+
+// Option can be passed into Search to configure it
+type Option func(*searcher)
+
+// WithWorkers sets the number of goroutines generating key pairs.
+// It defaults to the number of CPUs.
+func WithWorkers(n int) Option {
+	return func(s *searcher) {
+		if n > 0 {
+			s.workers = n
+		}
+	}
+}
+
+// WithProgress calls fn every interval with the progress of the search
+func WithProgress(fn func(Progress), interval time.Duration) Option {
+	return func(s *searcher) {
+		s.progress = fn
+		s.interval = interval
+	}
+}
+
+// batch is the number of attempts a worker makes between updates of the
+// shared attempt counter
+const batch = 64
+
+// Search generates random key pairs until one has an address matching
+// the pattern, or the context is cancelled
+func Search(parent context.Context, p Pattern, opts ...Option) (Result, error) {
+	s := searcher{workers: runtime.NumCPU(), interval: time.Second}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var attempts uint64
+	start := time.Now()
+	found := make(chan Result, 1)
+	errs := make(chan error, s.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// attempts not yet added to the shared counter
+			var pending uint64
+			for {
+				if pending == 0 {
+					select {
+					case <-ctx.Done():
+						return
+					default:
+					}
+				}
+				kp, err := crypto.NewKeyPair()
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+				pending++
+				a := kp.Address(p.Network)
+				if p.Match(a) {
+					total := atomic.AddUint64(&attempts, pending)
+					select {
+					case found <- Result{KeyPair: kp, Address: a, Attempts: total, Elapsed: time.Since(start)}:
+					default:
+					}
+					cancel()
+					return
+				}
+				if pending == batch {
+					atomic.AddUint64(&attempts, pending)
+					pending = 0
+				}
+			}
+		}()
+	}
+
+	if s.progress != nil && s.interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t := time.NewTicker(s.interval)
+			defer t.Stop()
+			expected := p.ExpectedAttempts()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-t.C:
+					n := atomic.LoadUint64(&attempts)
+					elapsed := time.Since(start)
+					s.progress(Progress{
+						Attempts: n,
+						Elapsed:  elapsed,
+						Rate:     float64(n) / elapsed.Seconds(),
+						Expected: expected})
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	select {
+	case r := <-found:
+		return r, nil
+	default:
+	}
+	select {
+	case err := <-errs:
+		return Result{}, err
+	default:
+	}
+	return Result{}, parent.Err()
+}
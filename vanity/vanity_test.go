@@ -0,0 +1,147 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vanity
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+const (
+	mainnet = byte(0x68)
+	testnet = byte(0x98)
+)
+
+func TestNewPatternInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		text    string
+		mode    Mode
+		network byte
+	}{
+		{"", Suffix, mainnet},
+		{"NAB0", Prefix, mainnet},
+		{"MYND", Prefix, mainnet},
+		{"NMYND", Prefix, mainnet},
+		{"TA", Prefix, mainnet},
+		{"NA", Prefix, testnet},
+		{"CAFE1", Contains, mainnet},
+		{"ABC", Mode(7), mainnet},
+		{strings.Repeat("A", 41), Suffix, mainnet},
+		{strings.Repeat("A", 40), Contains, mainnet},
+	} {
+		if _, err := NewPattern(tc.text, tc.mode, tc.network); err == nil {
+			t.Fatalf("expected an error for %+v", tc)
+		}
+	}
+}
+
+func TestNewPattern(t *testing.T) {
+	p, err := NewPattern("ndmynd", Prefix, mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Text != "NDMYND" {
+		t.Fatalf("\nWanted: %v\n   Got: %v", "NDMYND", p.Text)
+	}
+	if _, err := NewPattern("TB", Prefix, testnet); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	a := "NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R4"
+	for _, tc := range []struct {
+		p    Pattern
+		want bool
+	}{
+		{Pattern{Text: "NDD2", Mode: Prefix}, true},
+		{Pattern{Text: "J4R4", Mode: Suffix}, true},
+		{Pattern{Text: "ENTM", Mode: Contains}, true},
+		{Pattern{Text: "J4R4", Mode: Prefix}, false},
+		{Pattern{Text: "NDD2", Mode: Suffix}, false},
+		{Pattern{Text: "MYND", Mode: Contains}, false},
+		// the network letter is not part of a Contains match
+		{Pattern{Text: "NDD", Mode: Contains}, false},
+	} {
+		if got := tc.p.Match(a); got != tc.want {
+			t.Fatalf("%+v: wanted %v, got %v", tc.p, tc.want, got)
+		}
+	}
+}
+
+func TestExpectedAttempts(t *testing.T) {
+	for _, tc := range []struct {
+		p    Pattern
+		want float64
+	}{
+		{Pattern{Text: "N", Mode: Prefix}, 1},
+		{Pattern{Text: "ND", Mode: Prefix}, 4},
+		{Pattern{Text: "NDMYND", Mode: Prefix}, 4 * 32 * 32 * 32 * 32},
+		{Pattern{Text: "AB", Mode: Suffix}, 32 * 32},
+		{Pattern{Text: "AB", Mode: Contains}, 32 * 32 / 38.0},
+	} {
+		if got := tc.p.ExpectedAttempts(); got != tc.want {
+			t.Fatalf("%+v: wanted %v, got %v", tc.p, tc.want, got)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	p, err := NewPattern("A", Suffix, testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Search(context.Background(), p, WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(r.Address, "A") || r.Attempts == 0 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if err := crypto.ValidateAddress(r.Address, testnet); err != nil {
+		t.Fatal(err)
+	}
+	if r.KeyPair.Address(testnet) != r.Address {
+		t.Fatal("expected the key pair to own the address")
+	}
+}
+
+func TestSearchCancel(t *testing.T) {
+	// practically impossible to find
+	p, err := NewPattern("NAAAAAAAAAAAAAAAAAAA", Prefix, mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reports int32
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = Search(ctx, p, WithWorkers(2), WithProgress(func(pr Progress) {
+		atomic.AddInt32(&reports, 1)
+		if pr.Expected != p.ExpectedAttempts() {
+			t.Errorf("unexpected progress %+v", pr)
+		}
+	}, 20*time.Millisecond))
+	if err != context.DeadlineExceeded {
+		t.Fatalf("\nWanted: %v\n   Got: %v", context.DeadlineExceeded, err)
+	}
+	if atomic.LoadInt32(&reports) == 0 {
+		t.Fatal("expected progress to be reported")
+	}
+}
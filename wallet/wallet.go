@@ -0,0 +1,356 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wallet reads and writes NanoWallet .wlt wallet files.
+// A .wlt file is base64 encoded JSON in which the private key of each
+// account is AES encrypted with a key derived from the wallet password.
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/myndshft/nemgo/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// AlgoPassEnc marks an account whose private key was imported and is
+	// stored encrypted
+	AlgoPassEnc = "pass:enc"
+	// AlgoPassBIP32 marks an account created by NanoWallet from a random
+	// private key, stored encrypted the same way as AlgoPassEnc
+	AlgoPassBIP32 = "pass:bip32"
+	// AlgoPass6k marks the accounts of a brain wallet. The private key of
+	// the primary account is derived from the wallet password and is not
+	// stored in the file, other accounts are stored encrypted.
+	AlgoPass6k = "pass:6k"
+	// AlgoTrezor marks an account held on a Trezor hardware wallet, which
+	// has no private key in the wallet file
+	AlgoTrezor = "trezor"
+
+	// passwordRounds is the number of SHA3-256 rounds NanoWallet applies
+	// to a password to derive the key encrypting private keys
+	passwordRounds = 20
+	// brainRounds is the number of SHA3-256 rounds NanoWallet applies to
+	// a brain wallet passphrase to derive the private key
+	brainRounds = 6000
+)
+
+// ErrWrongPassword is returned when a private key does not decrypt to
+// the address stored for the account
+var ErrWrongPassword = errors.New("wrong wallet password")
+
+// Wallet is a NanoWallet wallet
+type Wallet struct {
+	// Name is the name of the wallet shown by NanoWallet
+	Name string `json:"name"`
+	// PrivateKey is unused by NanoWallet and always empty
+	PrivateKey string `json:"privateKey"`
+	// Accounts are keyed by their index as a string, "0" being the
+	// primary account
+	Accounts map[string]Account `json:"accounts"`
+}
+
+// Account is a single account stored in a wallet
+type Account struct {
+	Brain bool `json:"brain"`
+	// Algo describes how the private key is stored
+	Algo string `json:"algo"`
+	// Encrypted is the hex encoded AES-256-CBC encrypted private key
+	Encrypted string `json:"encrypted"`
+	// IV is the hex encoded initialisation vector used to encrypt the
+	// private key
+	IV      string `json:"iv"`
+	Address string `json:"address"`
+	Label   string `json:"label"`
+	// Network is the network byte as a signed integer, 104 for the
+	// mainnet and -104 for the testnet
+	Network int `json:"network"`
+	// Child is the public key of the account's first HD child, used by
+	// NanoWallet for dedicated apostille accounts
+	Child string `json:"child"`
+}
+
+// Parse decodes the contents of a .wlt file
+func Parse(data []byte) (Wallet, error) {
+	var w Wallet
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return w, errors.Wrap(err, "wallet is not valid base64")
+	}
+	if err := json.Unmarshal(raw, &w); err != nil {
+		return w, errors.Wrap(err, "wallet is not valid JSON")
+	}
+	if len(w.Accounts) == 0 {
+		return w, errors.New("wallet has no accounts")
+	}
+	return w, nil
+}
+
+// Read decodes a .wlt file from r
+func Read(r io.Reader) (Wallet, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Wallet{}, err
+	}
+	return Parse(data)
+}
+
+// Encode returns the wallet in the .wlt file format
+func (w Wallet) Encode() ([]byte, error) {
+	raw, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(raw)))
+	base64.StdEncoding.Encode(out, raw)
+	return out, nil
+}
+
+// Write writes the wallet to w in the .wlt file format
+func (w Wallet) Write(wr io.Writer) error {
+	data, err := w.Encode()
+	if err != nil {
+		return err
+	}
+	_, err = wr.Write(data)
+	return err
+}
+
+// New creates a wallet whose primary account holds the given key pair
+func New(name, password string, kp crypto.KeyPair, network byte) (Wallet, error) {
+	w := Wallet{Name: name, Accounts: map[string]Account{}}
+	if err := w.AddAccount("Primary", password, kp, network); err != nil {
+		return Wallet{}, err
+	}
+	return w, nil
+}
+
+// NewBrain creates a brain wallet whose primary account is derived from
+// the passphrase. The private key is not stored in the file, the
+// passphrase is needed to recover it.
+func NewBrain(name, passphrase string, network byte) (Wallet, error) {
+	kp, err := BrainKeyPair(passphrase)
+	if err != nil {
+		return Wallet{}, err
+	}
+	return Wallet{Name: name, Accounts: map[string]Account{
+		"0": {
+			Brain:   true,
+			Algo:    AlgoPass6k,
+			Address: kp.Address(network),
+			Label:   "Primary",
+			Network: int(int8(network))}}}, nil
+}
+
+// BrainKeyPair derives the key pair of a brain wallet from its
+// passphrase by hashing it 6000 times with SHA3-256, the same way
+// NanoWallet does, so the account has the same address in both.
+func BrainKeyPair(passphrase string) (crypto.KeyPair, error) {
+	if passphrase == "" {
+		return crypto.KeyPair{}, errors.New("passphrase must not be empty")
+	}
+	return crypto.FromPrivateKey(derivePassSha([]byte(passphrase), brainRounds))
+}
+
+// AddAccount encrypts the key pair with the wallet password and stores
+// it as the next account of the wallet
+func (w *Wallet) AddAccount(label, password string, kp crypto.KeyPair, network byte) error {
+	return w.addAccount(rand.Reader, label, password, kp, network)
+}
+
+func (w *Wallet) addAccount(r io.Reader, label, password string, kp crypto.KeyPair, network byte) error {
+	if len(kp.Private) != crypto.PrivateKeySize {
+		return errors.Errorf("private key must be %d bytes, got %d", crypto.PrivateKeySize, len(kp.Private))
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(r, iv); err != nil {
+		return errors.Wrap(err, "unable to read random iv")
+	}
+	block, err := aes.NewCipher(passwordKey(password))
+	if err != nil {
+		return err
+	}
+	padded := pad(kp.Private)
+	ct := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, padded)
+	if w.Accounts == nil {
+		w.Accounts = map[string]Account{}
+	}
+	w.Accounts[strconv.Itoa(len(w.Accounts))] = Account{
+		Algo:      AlgoPassEnc,
+		Encrypted: hex.EncodeToString(ct),
+		IV:        hex.EncodeToString(iv),
+		Address:   kp.Address(network),
+		Label:     label,
+		Network:   int(int8(network))}
+	return nil
+}
+
+// Indexes returns the keys of the wallet accounts in order
+func (w Wallet) Indexes() []string {
+	keys := make([]string, 0, len(w.Accounts))
+	for k := range w.Accounts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+	return keys
+}
+
+// KeyPair decrypts the account stored at index with the wallet password
+func (w Wallet) KeyPair(index string, password string) (crypto.KeyPair, error) {
+	acc, ok := w.Accounts[index]
+	if !ok {
+		return crypto.KeyPair{}, errors.Errorf("wallet has no account %q", index)
+	}
+	return acc.KeyPair(password)
+}
+
+// KeyPairs decrypts every account of the wallet in index order
+func (w Wallet) KeyPairs(password string) ([]crypto.KeyPair, error) {
+	var kps []crypto.KeyPair
+	for _, i := range w.Indexes() {
+		kp, err := w.KeyPair(i, password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "account %s", i)
+		}
+		kps = append(kps, kp)
+	}
+	return kps, nil
+}
+
+// NetworkByte returns the network byte of the account
+func (a Account) NetworkByte() byte {
+	return byte(int8(a.Network))
+}
+
+// KeyPair decrypts the private key of the account with the wallet
+// password, or derives it from the password for the primary account of
+// a brain wallet, which has no encrypted private key.
+// ErrWrongPassword is returned if the key does not match the address of
+// the account.
+func (a Account) KeyPair(password string) (crypto.KeyPair, error) {
+	switch a.Algo {
+	case AlgoPassEnc, AlgoPassBIP32:
+	case AlgoPass6k:
+		// only the primary account of a brain wallet is derived from the
+		// password, other accounts are stored encrypted
+		if a.Encrypted != "" || a.IV != "" {
+			break
+		}
+		kp, err := BrainKeyPair(password)
+		if err != nil {
+			return crypto.KeyPair{}, err
+		}
+		if kp.Address(a.NetworkByte()) != a.Address {
+			return crypto.KeyPair{}, ErrWrongPassword
+		}
+		return kp, nil
+	default:
+		return crypto.KeyPair{}, errors.Errorf("unsupported wallet algorithm %q", a.Algo)
+	}
+	ct, err := hex.DecodeString(a.Encrypted)
+	if err != nil {
+		return crypto.KeyPair{}, errors.Wrap(err, "encrypted private key is not valid hex")
+	}
+	iv, err := hex.DecodeString(a.IV)
+	if err != nil {
+		return crypto.KeyPair{}, errors.Wrap(err, "iv is not valid hex")
+	}
+	if len(iv) != aes.BlockSize || len(ct) == 0 || len(ct)%aes.BlockSize != 0 {
+		return crypto.KeyPair{}, errors.New("encrypted private key is malformed")
+	}
+	block, err := aes.NewCipher(passwordKey(password))
+	if err != nil {
+		return crypto.KeyPair{}, err
+	}
+	pt := make([]byte, len(ct))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(pt, ct)
+	priv, err := unpad(pt)
+	if err != nil {
+		return crypto.KeyPair{}, ErrWrongPassword
+	}
+	// legacy private keys have a leading zero byte
+	if len(priv) == crypto.PrivateKeySize+1 && priv[0] == 0 {
+		priv = priv[1:]
+	}
+	if len(priv) != crypto.PrivateKeySize {
+		return crypto.KeyPair{}, ErrWrongPassword
+	}
+	kp, err := crypto.FromPrivateKey(priv)
+	if err != nil {
+		return crypto.KeyPair{}, err
+	}
+	if kp.Address(a.NetworkByte()) != a.Address {
+		return crypto.KeyPair{}, ErrWrongPassword
+	}
+	return kp, nil
+}
+
+// passwordKey derives the AES key protecting private keys from the
+// wallet password
+func passwordKey(password string) []byte {
+	return derivePassSha([]byte(password), passwordRounds)
+}
+
+// derivePassSha hashes data with SHA3-256 count times
+func derivePassSha(data []byte, count int) []byte {
+	for i := 0; i < count; i++ {
+		h := sha3.NewLegacyKeccak256()
+		h.Write(data)
+		data = h.Sum(nil)
+	}
+	return data
+}
+
+// pad applies PKCS#7 padding
+func pad(b []byte) []byte {
+	n := aes.BlockSize - len(b)%aes.BlockSize
+	return append(append([]byte{}, b...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// unpad removes PKCS#7 padding
+func unpad(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, errors.New("invalid padding")
+	}
+	n := int(b[len(b)-1])
+	if n == 0 || n > aes.BlockSize || n > len(b) {
+		return nil, errors.New("invalid padding")
+	}
+	for _, p := range b[len(b)-n:] {
+		if int(p) != n {
+			return nil, errors.New("invalid padding")
+		}
+	}
+	return b[:len(b)-n], nil
+}
@@ -0,0 +1,312 @@
+// Copyright 2018 Myndshft Technologies, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/myndshft/nemgo/crypto"
+)
+
+const (
+	testnet = byte(0x98)
+	mainnet = byte(0x68)
+	privKey = "575dbb3062267eff57c970a336ebbc8fbcfe12c5bd3ed7bc11eb0481d7704ced"
+)
+
+func TestNewEncodeParse(t *testing.T) {
+	kp, err := crypto.FromHex(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := New("support", "correct horse", kp, mainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := w.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the file must be base64 encoded JSON
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(w, got) {
+		t.Fatalf("\nWanted: %+v\n   Got: %+v", w, got)
+	}
+	acc := got.Accounts["0"]
+	if acc.Address != "NDD2CT6LQLIYQ56KIXI3ENTM6EK3D44P5JFXJ4R4" || acc.Network != 104 || acc.Algo != AlgoPassEnc {
+		t.Fatalf("unexpected account %+v", acc)
+	}
+	dec, err := got.KeyPair("0", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.PrivateKeyString() != privKey {
+		t.Fatalf("\nWanted: %v\n   Got: %v", privKey, dec.PrivateKeyString())
+	}
+}
+
+func TestWrongPassword(t *testing.T) {
+	kp, err := crypto.FromHex(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := New("support", "correct horse", kp, testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.KeyPair("0", "battery staple"); err != ErrWrongPassword {
+		t.Fatalf("\nWanted: %v\n   Got: %v", ErrWrongPassword, err)
+	}
+	if _, err := w.KeyPair("1", "correct horse"); err == nil {
+		t.Fatal("expected an error for a missing account")
+	}
+}
+
+// nem-sdk test vector of a private key encrypted with the password
+// TestTest
+const (
+	knownPassword  = "TestTest"
+	knownKey       = "8cd87bc513857a7079d182a6e19b370e907107d97bd3f81a85bcebcc4b5bd3b5"
+	knownPrivKey   = "2a91e1d5c110a8d0105aad4683f962c2a56663a3cad46666b16d243174673d90"
+	knownEncrypted = "c09ef3ed0cadd6ca6d3638b5dd854ac871a0afaec6b7fed791166b571a64d57f564376dc0180c851b0a1120b5896e6a0"
+	knownIV        = "0329814121c7a4bb11418084dbe40560"
+)
+
+func TestKnownCiphertext(t *testing.T) {
+	if got := hex.EncodeToString(passwordKey(knownPassword)); got != knownKey {
+		t.Fatalf("\nWanted: %v\n   Got: %v", knownKey, got)
+	}
+	kp, err := crypto.FromHex(knownPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv, err := hex.DecodeString(knownIV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w Wallet
+	if err := w.addAccount(bytes.NewReader(iv), "Primary", knownPassword, kp, testnet); err != nil {
+		t.Fatal(err)
+	}
+	acc := w.Accounts["0"]
+	if acc.Encrypted != knownEncrypted || acc.IV != knownIV {
+		t.Fatalf("\nWanted: %v %v\n   Got: %v %v", knownEncrypted, knownIV, acc.Encrypted, acc.IV)
+	}
+	if acc.Network != -104 || acc.NetworkByte() != testnet {
+		t.Fatalf("unexpected network %d", acc.Network)
+	}
+	dec, err := acc.KeyPair(knownPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.PrivateKeyString() != knownPrivKey {
+		t.Fatalf("\nWanted: %v\n   Got: %v", knownPrivKey, dec.PrivateKeyString())
+	}
+}
+
+func TestLegacyPrivateKey(t *testing.T) {
+	kp, err := crypto.FromHex(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := bytes.Repeat([]byte{0x01}, aes.BlockSize)
+	block, err := aes.NewCipher(passwordKey("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the key was stored as 00 followed by its 32 bytes
+	padded := pad(append([]byte{0}, kp.Private...))
+	ct := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, padded)
+	acc := Account{
+		Algo:      AlgoPassEnc,
+		Encrypted: hex.EncodeToString(ct),
+		IV:        hex.EncodeToString(iv),
+		Address:   kp.Address(testnet),
+		Network:   -104}
+	dec, err := acc.KeyPair("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.PrivateKeyString() != privKey {
+		t.Fatalf("\nWanted: %v\n   Got: %v", privKey, dec.PrivateKeyString())
+	}
+	if _, err := acc.KeyPair("battery staple"); err != ErrWrongPassword {
+		t.Fatalf("\nWanted: %v\n   Got: %v", ErrWrongPassword, err)
+	}
+}
+
+func TestBrainChildAccount(t *testing.T) {
+	kp, err := crypto.FromHex(knownPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// accounts added to a brain wallet are encrypted with the password
+	acc := Account{
+		Brain:     true,
+		Algo:      AlgoPass6k,
+		Encrypted: knownEncrypted,
+		IV:        knownIV,
+		Address:   kp.Address(testnet),
+		Network:   -104}
+	dec, err := acc.KeyPair(knownPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.PrivateKeyString() != knownPrivKey {
+		t.Fatalf("\nWanted: %v\n   Got: %v", knownPrivKey, dec.PrivateKeyString())
+	}
+	acc.Encrypted = ""
+	if _, err := acc.KeyPair(knownPassword); err == nil {
+		t.Fatal("expected an error for an account with an iv only")
+	}
+}
+
+func TestKeyPairs(t *testing.T) {
+	w := Wallet{Name: "treasury"}
+	var want []string
+	for i := 0; i < 12; i++ {
+		kp, err := crypto.NewKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.AddAccount("", "pw", kp, testnet); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, kp.PrivateKeyString())
+	}
+	kps, err := w.KeyPairs("pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, kp := range kps {
+		if kp.PrivateKeyString() != want[i] {
+			t.Fatalf("account %d out of order", i)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("{")),
+		base64.StdEncoding.EncodeToString([]byte(`{"name":"x","accounts":{}}`)),
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestUnsupportedAlgo(t *testing.T) {
+	acc := Account{Algo: AlgoTrezor}
+	if _, err := acc.KeyPair("pw"); err == nil {
+		t.Fatal("expected an error for a trezor account")
+	}
+}
+
+func TestBrainKeyPair(t *testing.T) {
+	// derivePassSha is checked against nem-sdk in TestKnownCiphertext,
+	// these pin the 6000 round derivation and the addresses on both
+	// networks
+	for _, tc := range []struct {
+		passphrase string
+		priv       string
+		mainnet    string
+		testnet    string
+	}{
+		{"TestTest",
+			"8fac70ea9aca3ae3418e25c0d31d9a0723e0a1790ae8fa97747c00dc0037472e",
+			"NCTIKLMIWKRZC3TRKD5JYZUQHV76LGS3TTSUIXM6",
+			"TCTIKLMIWKRZC3TRKD5JYZUQHV76LGS3TSILYKRC"},
+		{"the quick brown fox jumps over the lazy dog",
+			"7acaba1f0432cd8b06f3b19f01bae365483ab6217cfebe0a3d4dd837502243cc",
+			"NA2K4WJUFY6BKXAMRJXJ4RQ2PYRBOHYRR7LALDMK",
+			"TA2K4WJUFY6BKXAMRJXJ4RQ2PYRBOHYRR6SHJKHZ"},
+	} {
+		kp, err := BrainKeyPair(tc.passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := kp.PrivateKeyString(); got != tc.priv {
+			t.Fatalf("%s\nWanted: %v\n   Got: %v", tc.passphrase, tc.priv, got)
+		}
+		if got := kp.Address(mainnet); got != tc.mainnet {
+			t.Fatalf("%s\nWanted: %v\n   Got: %v", tc.passphrase, tc.mainnet, got)
+		}
+		if got := kp.Address(testnet); got != tc.testnet {
+			t.Fatalf("%s\nWanted: %v\n   Got: %v", tc.passphrase, tc.testnet, got)
+		}
+	}
+	if _, err := BrainKeyPair(""); err == nil {
+		t.Fatal("expected an error for an empty passphrase")
+	}
+}
+
+func TestNewBrain(t *testing.T) {
+	w, err := NewBrain("brain", "the quick brown fox jumps over the lazy dog", testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := w.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := parsed.Accounts["0"]
+	if !acc.Brain || acc.Algo != AlgoPass6k || acc.Encrypted != "" {
+		t.Fatalf("unexpected account %+v", acc)
+	}
+	kp, err := parsed.KeyPair("0", "the quick brown fox jumps over the lazy dog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kp.Address(testnet) != acc.Address {
+		t.Fatalf("\nWanted: %v\n   Got: %v", acc.Address, kp.Address(testnet))
+	}
+	if _, err := parsed.KeyPair("0", "the quick brown fox"); err != ErrWrongPassword {
+		t.Fatalf("\nWanted: %v\n   Got: %v", ErrWrongPassword, err)
+	}
+}
+
+func TestDerivePassSha(t *testing.T) {
+	// a single round is plain SHA3-256
+	got := derivePassSha([]byte("abc"), 1)
+	want := "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"
+	if hex.EncodeToString(got) != want {
+		t.Fatalf("\nWanted: %v\n   Got: %x", want, got)
+	}
+}